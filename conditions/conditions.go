@@ -21,6 +21,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// PausedConditionType is the type of the condition reporting whether the reconciliation of an object is paused.
+	PausedConditionType ConditionType = "Paused"
+
+	// PausedReason is the reason set in the Paused condition when the reconciliation of an object is paused.
+	PausedReason ConditionReason = "Paused"
+	// ResumedReason is the reason set in the Paused condition when the reconciliation of an object is resumed.
+	ResumedReason ConditionReason = "Resumed"
+)
+
 // ConditionReason is a string representing a Kubernetes condition reason.
 type ConditionReason string
 
@@ -36,6 +46,12 @@ func (ct ConditionType) String() string {
 	return string(ct)
 }
 
+// Object is an interface that should be implemented by objects whose status contains a list of conditions, allowing
+// them to be managed without knowing their concrete type.
+type Object interface {
+	GetConditions() *[]metav1.Condition
+}
+
 // SetCondition creates a new condition with the given conditionType, status and reason. Then, it sets this new condition,
 // unsetting previous conditions with the same type as necessary.
 func SetCondition(conditions *[]metav1.Condition, conditionType ConditionType, status metav1.ConditionStatus, reason ConditionReason) {
@@ -0,0 +1,178 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/operator-toolkit/metadata"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AsyncTaskAnnotationPrefix is the prefix of the annotations used to track the state of asynchronous tasks. The
+	// handle of a task is stored in the annotation named after the task, while the times at which it was started and
+	// finished are stored in the same annotation with the "-started" and "-finished" suffixes.
+	AsyncTaskAnnotationPrefix = "async-task.operator-toolkit.konflux-ci.dev/"
+
+	// DefaultAsyncTaskPollInterval is the interval between polls used by asynchronous tasks not defining one.
+	DefaultAsyncTaskPollInterval = 30 * time.Second
+)
+
+// ErrAsyncTaskTimeout is the error returned when an asynchronous task doesn't finish within its timeout.
+var ErrAsyncTaskTimeout = errors.New("asynchronous task timed out")
+
+// AsyncTask defines a long-running task started by an operation and polled on later reconciles until it finishes.
+type AsyncTask struct {
+	// Name identifies the task within the object. It's used to name the annotations tracking the task state
+	Name string
+
+	// Start starts the task, returning a handle that can be used to poll it. As the handle is stored in the object
+	// once the task has started, Start should be idempotent in case storing it fails
+	Start func(ctx context.Context) (handle string, err error)
+
+	// Poll returns whether the task identified by the given handle has finished
+	Poll func(ctx context.Context, handle string) (done bool, err error)
+
+	// PollInterval is the delay after which the object is requeued to poll the task. Defaults to
+	// DefaultAsyncTaskPollInterval
+	PollInterval time.Duration
+
+	// Timeout is the maximum time the task can take to finish since it was started. No timeout is enforced if zero
+	Timeout time.Duration
+
+	// StopOnTimeout makes the processing stop when the task times out instead of failing with an error wrapping
+	// ErrAsyncTaskTimeout
+	StopOnTimeout bool
+}
+
+// Operation returns a ContextOperation driving the task for the given object. The first time it's invoked, the task is
+// started and its handle and start time are stored in annotations, patched using an optimistic lock. On later
+// invocations, the task is polled and the object requeued after the poll interval until the task finishes. Once it
+// finishes, the time is stored in an annotation and the processing continues, also on later reconciles, without
+// polling the task again. If the task times out, the operation fails with a terminal error or, if StopOnTimeout is
+// set, stops the processing.
+func (t *AsyncTask) Operation(cli client.Client, object client.Object) ContextOperation {
+	return func(ctx context.Context) (OperationResult, error) {
+		annotations := object.GetAnnotations()
+		if _, ok := annotations[t.finishedAnnotation()]; ok {
+			return ContinueProcessing()
+		}
+
+		handle, ok := annotations[t.handleAnnotation()]
+		if !ok {
+			return t.start(ctx, cli, object)
+		}
+
+		started, err := time.Parse(time.RFC3339, annotations[t.startedAnnotation()])
+		if err != nil {
+			return RequeueOnErrorOrStop(TerminalError(fmt.Errorf("invalid start time of task %q: %w", t.Name, err)))
+		}
+
+		if t.Timeout > 0 && time.Since(started) > t.Timeout {
+			if t.StopOnTimeout {
+				return StopProcessing()
+			}
+			return RequeueOnErrorOrStop(TerminalError(
+				fmt.Errorf("%w: task %q didn't finish after %s", ErrAsyncTaskTimeout, t.Name, t.Timeout)))
+		}
+
+		done, err := t.Poll(ctx, handle)
+		if err != nil {
+			return RequeueWithError(err)
+		}
+		if !done {
+			return RequeueAfter(t.pollDelay(started), nil)
+		}
+
+		return RequeueOnErrorOrContinue(t.patchAnnotations(ctx, cli, object, map[string]string{
+			t.finishedAnnotation(): time.Now().UTC().Format(time.RFC3339),
+		}))
+	}
+}
+
+// start starts the task and stores its handle and start time in the object annotations.
+func (t *AsyncTask) start(ctx context.Context, cli client.Client, object client.Object) (OperationResult, error) {
+	handle, err := t.Start(ctx)
+	if err != nil {
+		return RequeueWithError(err)
+	}
+
+	started := time.Now()
+	err = t.patchAnnotations(ctx, cli, object, map[string]string{
+		t.handleAnnotation():  handle,
+		t.startedAnnotation(): started.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return RequeueWithError(err)
+	}
+
+	return RequeueAfter(t.pollDelay(started), nil)
+}
+
+// patchAnnotations adds the given annotations to the object, sending a merge patch with an optimistic lock. The patch
+// is sent with a copy of the object, so only the annotations and the resource version returned by the server are copied
+// back into the object, keeping the changes made to it in memory, such as a status not patched yet.
+func (t *AsyncTask) patchAnnotations(ctx context.Context, cli client.Client, object client.Object, annotations map[string]string) error {
+	modified := object.DeepCopyObject().(client.Object)
+	if err := metadata.AddAnnotations(modified, annotations); err != nil {
+		return err
+	}
+
+	if err := cli.Patch(ctx, modified, client.MergeFromWithOptions(object, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	object.SetAnnotations(modified.GetAnnotations())
+	object.SetResourceVersion(modified.GetResourceVersion())
+
+	return nil
+}
+
+// pollDelay returns the delay after which the task should be polled, never exceeding its timeout.
+func (t *AsyncTask) pollDelay(started time.Time) time.Duration {
+	delay := t.PollInterval
+	if delay <= 0 {
+		delay = DefaultAsyncTaskPollInterval
+	}
+
+	if t.Timeout > 0 {
+		remaining := time.Until(started.Add(t.Timeout))
+		if remaining < delay {
+			delay = max(remaining, time.Second)
+		}
+	}
+
+	return delay
+}
+
+// handleAnnotation returns the name of the annotation storing the task handle.
+func (t *AsyncTask) handleAnnotation() string {
+	return AsyncTaskAnnotationPrefix + t.Name
+}
+
+// startedAnnotation returns the name of the annotation storing the time at which the task was started.
+func (t *AsyncTask) startedAnnotation() string {
+	return t.handleAnnotation() + "-started"
+}
+
+// finishedAnnotation returns the name of the annotation storing the time at which the task finished.
+func (t *AsyncTask) finishedAnnotation() string {
+	return t.handleAnnotation() + "-finished"
+}
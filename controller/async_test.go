@@ -0,0 +1,201 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("AsyncTask", func() {
+	const (
+		handleAnnotation   = AsyncTaskAnnotationPrefix + "build"
+		startedAnnotation  = AsyncTaskAnnotationPrefix + "build-started"
+		finishedAnnotation = AsyncTaskAnnotationPrefix + "build-finished"
+	)
+
+	var (
+		done      bool
+		k8sClient client.Client
+		object    *testObject
+		polled    []string
+		starts    int
+		task      *AsyncTask
+	)
+
+	invoke := func() (OperationResult, error) {
+		return task.Operation(k8sClient, object)(context.Background())
+	}
+
+	fetch := func() *testObject {
+		fetched := &testObject{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+		return fetched
+	}
+
+	ginkgo.BeforeEach(func() {
+		done, polled, starts = false, nil, 0
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(newTestScheme()).
+			WithObjects(&testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}).
+			WithStatusSubresource(&testObject{}).
+			Build()
+
+		object = &testObject{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "object", Namespace: "default"}, object)).To(Succeed())
+
+		task = &AsyncTask{
+			Name: "build",
+			Start: func(ctx context.Context) (string, error) {
+				starts++
+				return "build-1", nil
+			},
+			Poll: func(ctx context.Context, handle string) (bool, error) {
+				polled = append(polled, handle)
+				return done, nil
+			},
+			PollInterval: time.Minute,
+		}
+	})
+
+	ginkgo.When("the task has not been started", func() {
+		ginkgo.It("should start it, store its handle and requeue after the poll interval", func() {
+			result, err := invoke()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Minute, RequeueRequest: true}))
+			Expect(starts).To(Equal(1))
+			Expect(polled).To(BeEmpty())
+
+			annotations := fetch().Annotations
+			Expect(annotations).To(HaveKeyWithValue(handleAnnotation, "build-1"))
+			Expect(annotations).To(HaveKey(startedAnnotation))
+			Expect(annotations).NotTo(HaveKey(finishedAnnotation))
+		})
+
+		ginkgo.It("should keep the status changes made before the task was started", func() {
+			handler := NewHandler("async", WithStatusPatch(k8sClient, MergePatchStrategy))
+			_, err := handler.Handle(context.Background(), object,
+				NamedOperation{Name: "setCondition", Operation: func(ctx context.Context) (OperationResult, error) {
+					conditions.SetCondition(object.GetConditions(), "Ready", metav1.ConditionFalse, "Building")
+					return ContinueProcessing()
+				}},
+				NamedOperation{Name: "build", Operation: task.Operation(k8sClient, object)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			fetched := fetch()
+			Expect(fetched.Annotations).To(HaveKeyWithValue(handleAnnotation, "build-1"))
+			Expect(meta.IsStatusConditionFalse(fetched.Status.Conditions, "Ready")).To(BeTrue())
+		})
+
+		ginkgo.It("should requeue with the error if the task fails to start", func() {
+			task.Start = func(ctx context.Context) (string, error) { return "", fmt.Errorf("start") }
+
+			result, err := invoke()
+			Expect(err).To(MatchError("start"))
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(fetch().Annotations).NotTo(HaveKey(handleAnnotation))
+		})
+
+		ginkgo.It("should use the default poll interval if none is set", func() {
+			task.PollInterval = 0
+
+			result, _ := invoke()
+			Expect(result.RequeueDelay).To(Equal(DefaultAsyncTaskPollInterval))
+		})
+	})
+
+	ginkgo.When("the task has been started", func() {
+		ginkgo.BeforeEach(func() {
+			_, err := invoke()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		ginkgo.It("should poll the task and requeue after the poll interval while it's running", func() {
+			result, err := invoke()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Minute, RequeueRequest: true}))
+			Expect(starts).To(Equal(1))
+			Expect(polled).To(Equal([]string{"build-1"}))
+		})
+
+		ginkgo.It("should requeue with the error if the task can't be polled", func() {
+			task.Poll = func(ctx context.Context, handle string) (bool, error) { return false, fmt.Errorf("poll") }
+
+			result, err := invoke()
+			Expect(err).To(MatchError("poll"))
+			Expect(result.RequeueRequest).To(BeTrue())
+		})
+
+		ginkgo.It("should record the task as finished and continue the processing once it finishes", func() {
+			done = true
+
+			result, err := invoke()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(fetch().Annotations).To(HaveKey(finishedAnnotation))
+
+			result, err = invoke()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(polled).To(HaveLen(1))
+			Expect(starts).To(Equal(1))
+		})
+
+		ginkgo.It("should not requeue after the timeout expires", func() {
+			task.Timeout = 10 * time.Second
+
+			result, _ := invoke()
+			Expect(result.RequeueDelay).To(BeNumerically("<=", 10*time.Second))
+		})
+	})
+
+	ginkgo.When("the task times out", func() {
+		ginkgo.BeforeEach(func() {
+			task.Timeout = time.Minute
+			object.Annotations = map[string]string{
+				handleAnnotation:  "build-1",
+				startedAnnotation: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+			}
+		})
+
+		ginkgo.It("should fail with a terminal timeout error", func() {
+			_, err := invoke()
+			Expect(err).To(MatchError(ErrAsyncTaskTimeout))
+			Expect(IsTerminalError(err)).To(BeTrue())
+			Expect(polled).To(BeEmpty())
+		})
+
+		ginkgo.It("should stop the processing if StopOnTimeout is set", func() {
+			task.StopOnTimeout = true
+
+			result, err := invoke()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{CancelRequest: true}))
+			Expect(polled).To(BeEmpty())
+		})
+	})
+})
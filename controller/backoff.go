@@ -0,0 +1,112 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultBackoff is the Backoff used by handlers not configured with WithBackoff.
+var DefaultBackoff = Backoff{
+	Base:   5 * time.Second,
+	Cap:    5 * time.Minute,
+	Jitter: 0.1,
+}
+
+type (
+	// Backoff defines how the delay used to requeue an object grows with its consecutive failures.
+	Backoff struct {
+		// Base is the delay used after the first failure. It doubles with every consecutive failure. The Base of
+		// DefaultBackoff is used if it's not positive
+		Base time.Duration
+		// Cap is the maximum delay before applying the jitter. The delay is not capped if zero
+		Cap time.Duration
+		// Jitter is the maximum fraction of the delay randomly added to it
+		Jitter float64
+	}
+
+	// backoffTracker keeps count of the consecutive failures of every object.
+	backoffTracker struct {
+		failures map[types.NamespacedName]int
+		mutex    sync.Mutex
+	}
+)
+
+// Delay returns the delay to be used after the given number of consecutive failures.
+func (b Backoff) Delay(failures int) time.Duration {
+	delay := b.Base
+	if delay <= 0 {
+		delay = DefaultBackoff.Base
+	}
+	for i := 1; i < failures; i++ {
+		if (b.Cap > 0 && delay >= b.Cap) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+
+	if b.Cap > 0 && delay > b.Cap {
+		delay = b.Cap
+	}
+
+	if b.Jitter > 0 {
+		jitter := rand.Float64() * b.Jitter * float64(delay) // #nosec G404 -- not security sensitive
+		if jitter >= float64(math.MaxInt64-delay) {
+			return math.MaxInt64
+		}
+		delay += time.Duration(jitter)
+	}
+
+	return delay
+}
+
+// WithBackoff sets the Backoff used by the Handler to compute the delay of operations requesting to requeue with
+// backoff. The consecutive failures are tracked by the Handler, so they are only remembered across reconciles if the
+// Handler is reused.
+func WithBackoff(backoff Backoff) HandlerOption {
+	return func(handler *Handler) {
+		handler.backoff = backoff
+	}
+}
+
+// newBackoffTracker returns a new backoffTracker with no failures recorded.
+func newBackoffTracker() *backoffTracker {
+	return &backoffTracker{failures: map[types.NamespacedName]int{}}
+}
+
+// fail records a new failure for the object with the given key and returns its number of consecutive failures.
+func (t *backoffTracker) fail(key types.NamespacedName) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.failures[key]++
+
+	return t.failures[key]
+}
+
+// reset forgets the failures of the object with the given key.
+func (t *backoffTracker) reset(key types.NamespacedName) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.failures, key)
+}
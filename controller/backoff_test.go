@@ -0,0 +1,133 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = ginkgo.Describe("Backoff", func() {
+
+	ginkgo.When("Delay is called", func() {
+		backoff := Backoff{Base: time.Second, Cap: 10 * time.Second}
+
+		ginkgo.It("should double the delay with every consecutive failure", func() {
+			Expect(backoff.Delay(1)).To(Equal(time.Second))
+			Expect(backoff.Delay(2)).To(Equal(2 * time.Second))
+			Expect(backoff.Delay(3)).To(Equal(4 * time.Second))
+		})
+
+		ginkgo.It("should not exceed the cap", func() {
+			Expect(backoff.Delay(5)).To(Equal(10 * time.Second))
+			Expect(backoff.Delay(1000)).To(Equal(10 * time.Second))
+		})
+
+		ginkgo.It("should not cap the delay if the cap is zero", func() {
+			backoff := Backoff{Base: time.Second}
+			Expect(backoff.Delay(2)).To(Equal(2 * time.Second))
+			Expect(backoff.Delay(11)).To(Equal(1024 * time.Second))
+		})
+
+		ginkgo.It("should not overflow when the delay is not capped", func() {
+			backoff := Backoff{Base: time.Second, Jitter: 1}
+			Expect(backoff.Delay(1000)).To(BeNumerically(">", 0))
+			Expect(Backoff{Base: time.Second}.Delay(1000)).To(BeNumerically(">", 0))
+		})
+
+		ginkgo.It("should add a bounded jitter", func() {
+			backoff := Backoff{Base: time.Second, Cap: 10 * time.Second, Jitter: 0.5}
+			for i := 0; i < 100; i++ {
+				Expect(backoff.Delay(1)).To(BeNumerically(">=", time.Second))
+				Expect(backoff.Delay(1)).To(BeNumerically("<=", 1500*time.Millisecond))
+			}
+		})
+
+		ginkgo.It("should use the default base if the base is not positive", func() {
+			backoff := Backoff{Cap: time.Minute}
+			Expect(backoff.Delay(1)).To(Equal(DefaultBackoff.Base))
+			Expect(backoff.Delay(2)).To(Equal(2 * DefaultBackoff.Base))
+			Expect(Backoff{Base: -time.Second}.Delay(1)).To(Equal(DefaultBackoff.Base))
+		})
+	})
+
+	ginkgo.When("an operation requests to requeue with backoff", func() {
+		var (
+			failing bool
+			handler *Handler
+			object  *testObject
+		)
+
+		operation := NamedOperation{
+			Name: "backoff",
+			Operation: func(ctx context.Context) (OperationResult, error) {
+				if failing {
+					return RequeueWithBackoff(fmt.Errorf("error"))
+				}
+				return ContinueProcessing()
+			},
+		}
+
+		ginkgo.BeforeEach(func() {
+			failing = true
+			handler = NewHandler("backoff", WithBackoff(Backoff{Base: time.Second, Cap: time.Minute}))
+			object = &testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}
+		})
+
+		ginkgo.It("should grow the delay with the object consecutive failures without returning the error", func() {
+			for _, expectedDelay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+				result, err := handler.Handle(context.Background(), object, operation)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{RequeueAfter: expectedDelay}))
+			}
+		})
+
+		ginkgo.It("should requeue the object if the backoff has no base", func() {
+			handler = NewHandler("backoff", WithBackoff(Backoff{Cap: time.Minute}))
+			result, err := handler.Handle(context.Background(), object, operation)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: DefaultBackoff.Base}))
+		})
+
+		ginkgo.It("should track the failures of every object independently", func() {
+			otherObject := &testObject{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}
+
+			_, _ = handler.Handle(context.Background(), object, operation)
+			result, _ := handler.Handle(context.Background(), otherObject, operation)
+			Expect(result.RequeueAfter).To(Equal(time.Second))
+		})
+
+		ginkgo.It("should reset the failures once the object is processed cleanly", func() {
+			_, _ = handler.Handle(context.Background(), object, operation)
+			_, _ = handler.Handle(context.Background(), object, operation)
+
+			failing = false
+			_, err := handler.Handle(context.Background(), object, operation)
+			Expect(err).NotTo(HaveOccurred())
+
+			failing = true
+			result, _ := handler.Handle(context.Background(), object, operation)
+			Expect(result.RequeueAfter).To(Equal(time.Second))
+		})
+	})
+})
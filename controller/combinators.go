@@ -0,0 +1,178 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// Parallel returns an Operation invoking all the given operations concurrently and waiting for all of them to finish.
+// The errors returned by the operations are joined and their results are merged deterministically: if any operation
+// cancels the request, the processing is stopped; otherwise, if any operation requests a requeue, the shortest delay
+// among all the requested ones is used. A panic raised by any of the operations is raised again in the calling
+// goroutine, carrying the stack trace of the goroutine that panicked, so it can be recovered by the Handler.
+func Parallel(operations ...Operation) Operation {
+	return func() (OperationResult, error) {
+		results := make([]OperationResult, len(operations))
+		errs := make([]error, len(operations))
+		panics := make([]any, len(operations))
+
+		var waitGroup sync.WaitGroup
+		for index, operation := range operations {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				defer func() {
+					if value := recover(); value != nil {
+						panics[index] = newGoroutinePanic(value)
+					}
+				}()
+
+				results[index], errs[index] = operation()
+			}()
+		}
+		waitGroup.Wait()
+
+		for _, value := range panics {
+			if value != nil {
+				panic(value)
+			}
+		}
+
+		return mergeResults(results), errors.Join(errs...)
+	}
+}
+
+// When returns an Operation invoking the given one only if the condition is met when the returned Operation is invoked.
+// Otherwise, the processing continues.
+func When(condition func() bool, operation Operation) Operation {
+	return func() (OperationResult, error) {
+		if !condition() {
+			return ContinueProcessing()
+		}
+
+		return operation()
+	}
+}
+
+// Unless returns an Operation invoking the given one only if the condition is not met when the returned Operation is
+// invoked. Otherwise, the processing continues.
+func Unless(condition func() bool, operation Operation) Operation {
+	return When(func() bool { return !condition() }, operation)
+}
+
+// FirstOf returns an Operation invoking the given operations in order until one of them doesn't return an error,
+// returning its result. If all of them fail, the result of the last one is returned along with all the errors joined.
+func FirstOf(operations ...Operation) Operation {
+	return func() (OperationResult, error) {
+		var result OperationResult
+		var errs []error
+
+		for _, operation := range operations {
+			var err error
+			result, err = operation()
+			if err == nil {
+				return result, nil
+			}
+			errs = append(errs, err)
+		}
+
+		return result, errors.Join(errs...)
+	}
+}
+
+// Sequence returns an Operation invoking the given operations in order, following the same rules as the Handler: the
+// sequence is interrupted by the first operation returning an error, requesting a requeue or stopping the processing,
+// and its result is returned so the interruption propagates to the caller. Deferred requeues requested by the
+// operations invoked are kept, using the shortest delay.
+func Sequence(operations ...Operation) Operation {
+	return func() (OperationResult, error) {
+		var requeueResults []OperationResult
+
+		for _, operation := range operations {
+			result, err := operation()
+			if err != nil || result.RequeueRequest || result.CancelRequest {
+				merged := mergeResults(appendRequeue(requeueResults, result))
+				merged.CancelRequest = result.CancelRequest
+
+				return merged, err
+			}
+			requeueResults = appendRequeue(requeueResults, result)
+		}
+
+		return mergeResults(requeueResults), nil
+	}
+}
+
+// RetryOnConflict returns an Operation invoking the given one again, following the given backoff, as long as it returns
+// a Conflict error, so a conflict doesn't requeue the whole reconcile. If refetch is not nil, it's called before every
+// retry so the operation can work with a fresh copy of the object; a failure to refetch interrupts the retries and
+// requeues the object with its error. Otherwise, the result of the last invocation is returned along with its error.
+// retry.DefaultRetry and retry.DefaultBackoff can be used as backoffs.
+func RetryOnConflict(operation Operation, backoff wait.Backoff, refetch func() error) Operation {
+	return func() (OperationResult, error) {
+		var result OperationResult
+		attempt := 0
+
+		err := retry.OnError(backoff, apierrors.IsConflict, func() error {
+			attempt++
+			if attempt > 1 && refetch != nil {
+				if err := refetch(); err != nil {
+					result = OperationResult{RequeueRequest: true}
+					return err
+				}
+			}
+
+			var err error
+			result, err = operation()
+
+			return err
+		})
+
+		return result, err
+	}
+}
+
+// mergeResults merges the given results into one. A result cancelling the request takes precedence over the rest.
+// Otherwise, the merged result requests a requeue, or a requeue with backoff, if any of the results did, using the
+// shortest non-zero delay among those requesting a requeue or continuing with a deferred requeue. Immediate requeues
+// don't override the delay, so a deferred requeue is never lost.
+func mergeResults(results []OperationResult) OperationResult {
+	var merged OperationResult
+
+	for _, result := range results {
+		switch {
+		case result.CancelRequest:
+			return result
+		case !result.RequeueRequest && result.RequeueDelay == 0:
+			continue
+		}
+
+		merged.RequeueRequest = merged.RequeueRequest || result.RequeueRequest
+		merged.BackoffRequest = merged.BackoffRequest || result.BackoffRequest
+		if result.RequeueDelay > 0 && (merged.RequeueDelay == 0 || result.RequeueDelay < merged.RequeueDelay) {
+			merged.RequeueDelay = result.RequeueDelay
+		}
+	}
+
+	return merged
+}
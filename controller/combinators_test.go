@@ -0,0 +1,361 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// panickingOperation is an Operation that panics, so it can be found in stack traces.
+func panickingOperation() (OperationResult, error) {
+	panic("panic")
+}
+
+var _ = ginkgo.Describe("Combinators", func() {
+
+	ginkgo.When("Parallel is called", func() {
+		ginkgo.It("should invoke all the operations concurrently", func() {
+			var invocations atomic.Int32
+			started := make(chan struct{})
+			operation := func() (OperationResult, error) {
+				if invocations.Add(1) == 2 {
+					close(started)
+				}
+				<-started
+				return ContinueProcessing()
+			}
+
+			result, err := Parallel(operation, operation)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(invocations.Load()).To(Equal(int32(2)))
+		})
+
+		ginkgo.It("should join the errors of all the operations", func() {
+			_, err := Parallel(
+				func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("first")) },
+				ContinueProcessing,
+				func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("second")) },
+			)()
+			Expect(err).To(MatchError("first\nsecond"))
+		})
+
+		ginkgo.It("should cancel the request if any of the operations cancels it", func() {
+			result, err := Parallel(
+				func() (OperationResult, error) { return RequeueAfter(time.Second, nil) },
+				StopProcessing,
+				ContinueProcessing,
+			)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeTrue())
+			Expect(result.RequeueRequest).To(BeFalse())
+		})
+
+		ginkgo.It("should requeue with the shortest delay", func() {
+			result, err := Parallel(
+				func() (OperationResult, error) { return RequeueAfter(time.Minute, nil) },
+				func() (OperationResult, error) { return RequeueAfter(time.Second, nil) },
+				ContinueProcessing,
+			)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(result.RequeueDelay).To(Equal(time.Second))
+		})
+
+		ginkgo.It("should keep the shortest deferred requeue when all the operations continue processing", func() {
+			result, err := Parallel(
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Second) },
+				ContinueProcessing,
+			)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Second}))
+		})
+
+		ginkgo.It("should keep the deferred requeue when another operation requests an immediate requeue", func() {
+			result, err := Parallel(
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				Requeue,
+			)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Minute, RequeueRequest: true}))
+		})
+
+		ginkgo.It("should raise the panics of the operations in the calling goroutine", func() {
+			Expect(func() {
+				_, _ = Parallel(ContinueProcessing, func() (OperationResult, error) { panic("panic") })()
+			}).To(PanicWith(WithTransform(func(value *goroutinePanic) any { return value.value }, Equal("panic"))))
+		})
+
+		ginkgo.It("should keep the stack trace of the goroutine that panicked", func() {
+			_, err := NewHandler("parallel", WithRecovery("")).Handle(context.Background(), nil, NamedOperation{
+				Name:      "parallel",
+				Operation: ToContextOperation(Parallel(ContinueProcessing, panickingOperation)),
+			})
+
+			var panicErr *PanicError
+			Expect(errors.As(err, &panicErr)).To(BeTrue())
+			Expect(panicErr.Value).To(Equal("panic"))
+			Expect(string(panicErr.Stack)).To(ContainSubstring("controller.panickingOperation("))
+		})
+	})
+
+	ginkgo.When("When is called", func() {
+		ginkgo.It("should invoke the operation when the condition is met", func() {
+			result, err := When(func() bool { return true }, StopProcessing)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{CancelRequest: true}))
+		})
+
+		ginkgo.It("should continue the processing without invoking the operation when the condition is not met", func() {
+			invoked := false
+			result, err := When(func() bool { return false }, func() (OperationResult, error) {
+				invoked = true
+				return StopProcessing()
+			})()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(invoked).To(BeFalse())
+		})
+
+		ginkgo.It("should evaluate the condition every time the operation is invoked", func() {
+			condition := false
+			operation := When(func() bool { return condition }, StopProcessing)
+
+			result, _ := operation()
+			Expect(result.CancelRequest).To(BeFalse())
+
+			condition = true
+			result, _ = operation()
+			Expect(result.CancelRequest).To(BeTrue())
+		})
+	})
+
+	ginkgo.When("Unless is called", func() {
+		ginkgo.It("should invoke the operation when the condition is not met", func() {
+			result, err := Unless(func() bool { return false }, StopProcessing)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{CancelRequest: true}))
+		})
+
+		ginkgo.It("should continue the processing without invoking the operation when the condition is met", func() {
+			result, err := Unless(func() bool { return true }, StopProcessing)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+		})
+	})
+
+	ginkgo.When("FirstOf is called", func() {
+		ginkgo.It("should return the result of the first operation not returning an error", func() {
+			var invocations atomic.Int32
+			result, err := FirstOf(
+				func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("error")) },
+				StopProcessing,
+				func() (OperationResult, error) {
+					invocations.Add(1)
+					return ContinueProcessing()
+				},
+			)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{CancelRequest: true}))
+			Expect(invocations.Load()).To(BeZero())
+		})
+
+		ginkgo.It("should return the result of the last operation and all the errors joined if all of them fail", func() {
+			result, err := FirstOf(
+				func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("first")) },
+				func() (OperationResult, error) { return RequeueAfter(time.Minute, fmt.Errorf("second")) },
+			)()
+			Expect(err).To(MatchError("first\nsecond"))
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Minute, RequeueRequest: true}))
+		})
+
+		ginkgo.It("should continue the processing when there are no operations", func() {
+			result, err := FirstOf()()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+		})
+	})
+
+	ginkgo.When("Sequence is called", func() {
+		ginkgo.It("should invoke all the operations in order when they continue the processing", func() {
+			var order []int
+			step := func(index int) Operation {
+				return func() (OperationResult, error) {
+					order = append(order, index)
+					return ContinueProcessing()
+				}
+			}
+
+			result, err := Sequence(step(1), step(2), step(3))()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(order).To(Equal([]int{1, 2, 3}))
+		})
+
+		ginkgo.It("should stop at the first operation stopping the processing and propagate the cancellation", func() {
+			invoked := false
+			result, err := Sequence(StopProcessing, func() (OperationResult, error) {
+				invoked = true
+				return ContinueProcessing()
+			})()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{CancelRequest: true}))
+			Expect(invoked).To(BeFalse())
+		})
+
+		ginkgo.It("should stop at the first operation returning an error or requesting a requeue", func() {
+			invoked := false
+			result, err := Sequence(
+				func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("error")) },
+				func() (OperationResult, error) {
+					invoked = true
+					return ContinueProcessing()
+				},
+			)()
+			Expect(err).To(MatchError("error"))
+			Expect(result).To(Equal(OperationResult{RequeueRequest: true}))
+			Expect(invoked).To(BeFalse())
+
+			result, err = Sequence(Requeue, StopProcessing)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{RequeueRequest: true}))
+		})
+
+		ginkgo.It("should keep the shortest deferred requeue of the operations invoked", func() {
+			result, err := Sequence(
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Hour) },
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+			)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Minute}))
+
+			result, _ = Sequence(
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				StopProcessing,
+			)()
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Minute, CancelRequest: true}))
+		})
+
+		ginkgo.It("should keep the deferred requeue when an operation requests an immediate requeue", func() {
+			result, err := Sequence(
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				Requeue,
+			)()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Minute, RequeueRequest: true}))
+
+			reconcileResult, err := ReconcileHandler([]Operation{Sequence(
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				Requeue,
+			)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconcileResult).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		})
+
+		ginkgo.It("should behave as the Handler when nested", func() {
+			operations := []Operation{
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Hour) },
+				Sequence(
+					func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+					StopProcessing,
+				),
+				func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("not reached")) },
+			}
+
+			nested, nestedErr := ReconcileHandler(operations)
+			flat, flatErr := ReconcileHandler([]Operation{Sequence(operations...)})
+			Expect(nestedErr).NotTo(HaveOccurred())
+			Expect(flatErr).NotTo(HaveOccurred())
+			Expect(nested).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(flat).To(Equal(nested))
+		})
+	})
+
+	ginkgo.When("RetryOnConflict is called", func() {
+		var (
+			backoff  wait.Backoff
+			conflict error
+		)
+
+		ginkgo.BeforeEach(func() {
+			backoff = wait.Backoff{Steps: 3, Duration: time.Millisecond}
+			conflict = apierrors.NewConflict(schema.GroupResource{Resource: "objects"}, "object", fmt.Errorf("conflict"))
+		})
+
+		ginkgo.It("should invoke the operation again while it returns a conflict", func() {
+			var invocations, refetches int
+			result, err := RetryOnConflict(func() (OperationResult, error) {
+				invocations++
+				if invocations < 3 {
+					return RequeueWithError(conflict)
+				}
+				return StopProcessing()
+			}, backoff, func() error {
+				refetches++
+				return nil
+			})()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{CancelRequest: true}))
+			Expect(invocations).To(Equal(3))
+			Expect(refetches).To(Equal(2))
+		})
+
+		ginkgo.It("should return the result and the conflict of the last invocation once the retries are exhausted", func() {
+			invocations := 0
+			result, err := RetryOnConflict(func() (OperationResult, error) {
+				invocations++
+				return RequeueAfter(time.Minute, conflict)
+			}, backoff, nil)()
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+			Expect(result).To(Equal(OperationResult{RequeueDelay: time.Minute, RequeueRequest: true}))
+			Expect(invocations).To(Equal(3))
+		})
+
+		ginkgo.It("should not retry errors other than conflicts", func() {
+			invocations := 0
+			_, err := RetryOnConflict(func() (OperationResult, error) {
+				invocations++
+				return RequeueWithError(fmt.Errorf("error"))
+			}, backoff, nil)()
+			Expect(err).To(MatchError("error"))
+			Expect(invocations).To(Equal(1))
+		})
+
+		ginkgo.It("should requeue the object with the refetch error when the refetch fails", func() {
+			invocations := 0
+			result, err := RetryOnConflict(func() (OperationResult, error) {
+				invocations++
+				return RequeueWithError(conflict)
+			}, backoff, func() error { return fmt.Errorf("refetch") })()
+			Expect(err).To(MatchError("refetch"))
+			Expect(result).To(Equal(OperationResult{RequeueRequest: true}))
+			Expect(invocations).To(Equal(1))
+		})
+	})
+})
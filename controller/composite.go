@@ -0,0 +1,122 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type (
+	// SubReconciler is a part of a CompositeController owning some of its watches and operations. If it implements
+	// CacheInitializer, its cache will be initialized when the CompositeController cache is.
+	SubReconciler[T client.Object] interface {
+		// Name returns the name of the SubReconciler, used to scope its logger and to prefix its operation names
+		Name() string
+
+		// Register adds the watches required by the SubReconciler to the builder shared by the CompositeController
+		Register(builder *builder.Builder, log *logr.Logger, cluster cluster.Cluster) error
+
+		// Operations returns the operations to be performed by the SubReconciler as part of the reconcile of the
+		// given object
+		Operations(ctx context.Context, object T) []NamedOperation
+	}
+
+	// CompositeController is a Controller aggregating several SubReconcilers, so large controllers can be split into
+	// smaller parts. It watches the objects created by newObject along with the watches added by its children, and
+	// reconciles them invoking the operations of every child in declared order. Operation names are prefixed with the
+	// name of the child owning them, and their loggers are named after it.
+	CompositeController[T client.Object] struct {
+		*Reconciler[T]
+
+		children []SubReconciler[T]
+		name     string
+	}
+)
+
+// NewCompositeController returns a new CompositeController with the given name aggregating the given children. The
+// client, handler and newObject arguments are used as in NewReconciler.
+func NewCompositeController[T client.Object](name string, cli client.Client, handler *Handler, newObject func() T, children ...SubReconciler[T]) *CompositeController[T] {
+	controller := &CompositeController[T]{
+		children: children,
+		name:     name,
+	}
+	controller.Reconciler = NewReconciler(cli, handler, newObject, controller.operations)
+
+	return controller
+}
+
+// Register creates a controller for the objects created by newObject and lets every child add its watches to it
+// before completing it. Each child receives a logger named after it.
+func (c *CompositeController[T]) Register(mgr ctrl.Manager, log *logr.Logger, cluster cluster.Cluster) error {
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).Named(c.name).For(c.newObject())
+
+	for _, child := range c.children {
+		childLog := log.WithName(child.Name())
+		if err := child.Register(controllerBuilder, &childLog, cluster); err != nil {
+			return fmt.Errorf("failed to register %q: %w", child.Name(), err)
+		}
+	}
+
+	return controllerBuilder.Complete(c)
+}
+
+// SetupCache initializes the cache of every child implementing CacheInitializer.
+func (c *CompositeController[T]) SetupCache(mgr ctrl.Manager) error {
+	for _, child := range c.children {
+		if cacheInitializer, ok := child.(CacheInitializer); ok {
+			if err := cacheInitializer.SetupCache(mgr); err != nil {
+				return fmt.Errorf("failed to set up cache of %q: %w", child.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// operations returns the operations of every child for the given object in declared order, prefixing their names
+// with the name of the child and naming their loggers after it.
+func (c *CompositeController[T]) operations(ctx context.Context, object T) []NamedOperation {
+	var operations []NamedOperation
+
+	for _, child := range c.children {
+		childCtx := childContext(ctx, child.Name())
+		for _, operation := range child.Operations(childCtx, object) {
+			contextOperation := operation.Operation
+			operation.Name = child.Name() + "/" + operation.Name
+			operation.Operation = func(ctx context.Context) (OperationResult, error) {
+				return contextOperation(childContext(ctx, child.Name()))
+			}
+			operations = append(operations, operation)
+		}
+	}
+
+	return operations
+}
+
+// childContext returns a context derived from the given one, carrying a logger named after the child with the given
+// name.
+func childContext(ctx context.Context, name string) context.Context {
+	return log.IntoContext(ctx, log.FromContext(ctx).WithName(name))
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// testSubReconciler is a SubReconciler recording the calls received.
+type testSubReconciler struct {
+	name          string
+	invoked       *[]string
+	registerError error
+	registered    bool
+}
+
+func (s *testSubReconciler) Name() string {
+	return s.name
+}
+
+func (s *testSubReconciler) Register(builder *builder.Builder, log *logr.Logger, cluster cluster.Cluster) error {
+	s.registered = builder != nil && log != nil
+	return s.registerError
+}
+
+func (s *testSubReconciler) Operations(ctx context.Context, object *testObject) []NamedOperation {
+	return []NamedOperation{{Name: "operation", Operation: func(ctx context.Context) (OperationResult, error) {
+		*s.invoked = append(*s.invoked, s.name)
+		log.FromContext(ctx).Info("invoked")
+		return ContinueProcessing()
+	}}}
+}
+
+// testCachedSubReconciler is a testSubReconciler implementing CacheInitializer.
+type testCachedSubReconciler struct {
+	testSubReconciler
+	cacheSetUp bool
+}
+
+func (s *testCachedSubReconciler) SetupCache(mgr ctrl.Manager) error {
+	s.cacheSetUp = true
+	return nil
+}
+
+var _ = ginkgo.Describe("CompositeController", func() {
+	var (
+		first, second *testSubReconciler
+		cached        *testCachedSubReconciler
+		composite     *CompositeController[*testObject]
+		invoked       []string
+		k8sClient     client.Client
+		loggers       []string
+	)
+
+	newManager := func() ctrl.Manager {
+		mgr, err := ctrl.NewManager(&rest.Config{Host: "http://localhost:1"}, ctrl.Options{
+			Scheme:     newTestScheme(),
+			Controller: config.Controller{SkipNameValidation: ptr.To(true)},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return mgr
+	}
+
+	ginkgo.BeforeEach(func() {
+		invoked, loggers = nil, nil
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(newTestScheme()).
+			WithObjects(&testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}).
+			Build()
+
+		first = &testSubReconciler{name: "first", invoked: &invoked}
+		second = &testSubReconciler{name: "second", invoked: &invoked}
+		cached = &testCachedSubReconciler{testSubReconciler: testSubReconciler{name: "cached", invoked: &invoked}}
+		composite = NewCompositeController[*testObject]("composite", k8sClient, nil,
+			func() *testObject { return &testObject{} }, first, second, cached)
+	})
+
+	ginkgo.When("Reconcile is called", func() {
+		ginkgo.It("should invoke the operations of every child in declared order with loggers named after them", func() {
+			logger := funcr.New(func(prefix, args string) {
+				loggers = append(loggers, prefix)
+			}, funcr.Options{})
+			ctx := log.IntoContext(context.Background(), logger)
+
+			result, err := composite.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "object", Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(invoked).To(Equal([]string{"first", "second", "cached"}))
+			Expect(loggers).To(Equal([]string{"first", "second", "cached"}))
+		})
+
+		ginkgo.It("should prefix the operation names with the name of the child", func() {
+			var names []string
+			composite.handler = NewHandler("composite", WithInterceptors(
+				func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+					names = append(names, info.Name)
+					return operation(ctx)
+				}))
+
+			_, err := composite.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "object", Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal([]string{"first/operation", "second/operation", "cached/operation"}))
+		})
+	})
+
+	ginkgo.When("Register is called", func() {
+		ginkgo.It("should let every child register its watches", func() {
+			logger := ctrl.Log
+			Expect(composite.Register(newManager(), &logger, nil)).To(Succeed())
+			Expect(first.registered).To(BeTrue())
+			Expect(second.registered).To(BeTrue())
+			Expect(cached.registered).To(BeTrue())
+		})
+
+		ginkgo.It("should fail if a child fails to register", func() {
+			second.registerError = fmt.Errorf("error")
+
+			logger := ctrl.Log
+			err := composite.Register(newManager(), &logger, nil)
+			Expect(err).To(MatchError(`failed to register "second": error`))
+			Expect(cached.registered).To(BeFalse())
+		})
+	})
+
+	ginkgo.When("SetupCache is called", func() {
+		ginkgo.It("should set up the cache of the children implementing CacheInitializer", func() {
+			Expect(composite.SetupCache(nil)).To(Succeed())
+			Expect(cached.cacheSetUp).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,217 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ActionVerb represents the type of write recorded in a Plan
+type ActionVerb string
+
+const (
+	// ActionApply represents an Apply write
+	ActionApply ActionVerb = "apply"
+	// ActionCreate represents a Create write
+	ActionCreate ActionVerb = "create"
+	// ActionDelete represents a Delete write
+	ActionDelete ActionVerb = "delete"
+	// ActionDeleteAllOf represents a DeleteAllOf write
+	ActionDeleteAllOf ActionVerb = "deleteallof"
+	// ActionPatch represents a Patch write
+	ActionPatch ActionVerb = "patch"
+	// ActionUpdate represents an Update write
+	ActionUpdate ActionVerb = "update"
+)
+
+type (
+	// PlannedAction is a write that would have been sent to the cluster if the operations weren't run in dry-run mode.
+	PlannedAction struct {
+		// Verb is the type of write
+		Verb ActionVerb
+		// SubResource is the name of the subresource written (e.g., "status"), if any
+		SubResource string
+		// GroupVersionKind is the kind of the object written, if it could be determined
+		GroupVersionKind schema.GroupVersionKind
+		// Key is the namespace and name of the object written
+		Key client.ObjectKey
+		// Object is a copy of the object as it was when the write was requested. It's nil for Apply writes
+		Object client.Object
+		// ApplyConfiguration is the configuration of Apply writes
+		ApplyConfiguration runtime.ApplyConfiguration
+		// PatchType is the type of the patch of Patch writes
+		PatchType types.PatchType
+		// Patch is the content of the patch of Patch writes
+		Patch []byte
+	}
+
+	// Plan contains the writes recorded by a dry-run client, in the order they were requested.
+	Plan struct {
+		Actions []PlannedAction
+
+		mutex sync.Mutex
+	}
+
+	// dryRunClient is a client recording the writes in a Plan instead of sending them, while reads are sent to the
+	// wrapped client.
+	dryRunClient struct {
+		client.Client
+		plan *Plan
+	}
+
+	// dryRunSubResourceClient wraps the subresource client to record the subresource writes
+	dryRunSubResourceClient struct {
+		client.SubResourceClient
+		dryRunClient    *dryRunClient
+		subResourceName string
+	}
+)
+
+// NewDryRunClient returns a client wrapping the given one that records creates, updates, patches and deletes, including
+// the ones sent to subresources, in the returned Plan instead of sending them. Reads are sent to the wrapped client,
+// so they don't reflect the recorded writes.
+func NewDryRunClient(cli client.Client) (client.Client, *Plan) {
+	plan := &Plan{}
+
+	return &dryRunClient{Client: cli, plan: plan}, plan
+}
+
+// DryRun invokes the operations built by the factory for the given object like Handle, but the operations receive a
+// dry-run client wrapping the given one, so the writes are recorded in the returned Plan instead of being sent. If
+// the Handler patches the object status, the patch is recorded as well, always as a merge patch as the response of a
+// server-side apply can't be simulated. The consecutive failures of the object used to compute backoff delays are
+// not modified. Interceptors are invoked as usual, except the ones added by WithEventRecorder, WithMetrics and
+// WithTracerProvider, so no events, metrics or spans are emitted.
+func (h *Handler) DryRun(ctx context.Context, object client.Object, cli client.Client, factory func(cli client.Client) []NamedOperation) (ctrl.Result, *Plan, error) {
+	dryRunCli, plan := NewDryRunClient(cli)
+
+	handler := *h
+	handler.backoffTracker = newBackoffTracker()
+	handler.interceptor = h.dryRunInterceptor
+	handler.tracer = nil
+	if h.statusPatcher != nil {
+		handler.statusPatcher = &statusPatcher{
+			client:     dryRunCli,
+			fieldOwner: h.statusPatcher.fieldOwner,
+			strategy:   MergePatchStrategy,
+		}
+	}
+
+	result, err := handler.Handle(ctx, object, factory(dryRunCli)...)
+
+	return result, plan, err
+}
+
+// record appends an action for the given object to the plan.
+func (c *dryRunClient) record(verb ActionVerb, subResource string, obj client.Object, patch client.Patch) error {
+	action := PlannedAction{
+		Verb:        verb,
+		SubResource: subResource,
+		Key:         client.ObjectKeyFromObject(obj),
+		Object:      obj.DeepCopyObject().(client.Object),
+	}
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		action.GroupVersionKind = gvk
+	}
+	if patch != nil {
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		action.PatchType = patch.Type()
+		action.Patch = data
+	}
+
+	c.plan.append(action)
+
+	return nil
+}
+
+// append adds the given action to the plan.
+func (p *Plan) append(action PlannedAction) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.Actions = append(p.Actions, action)
+}
+
+// Apply implements client.Client
+func (c *dryRunClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	c.plan.append(PlannedAction{Verb: ActionApply, ApplyConfiguration: obj})
+	return nil
+}
+
+// Create implements client.Client
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.record(ActionCreate, "", obj, nil)
+}
+
+// Delete implements client.Client
+func (c *dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.record(ActionDelete, "", obj, nil)
+}
+
+// DeleteAllOf implements client.Client
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	return c.record(ActionDeleteAllOf, "", obj, nil)
+}
+
+// Patch implements client.Client
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.record(ActionPatch, "", obj, patch)
+}
+
+// Update implements client.Client
+func (c *dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.record(ActionUpdate, "", obj, nil)
+}
+
+// Status implements client.Client
+func (c *dryRunClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+// SubResource implements client.Client
+func (c *dryRunClient) SubResource(subResource string) client.SubResourceClient {
+	return &dryRunSubResourceClient{
+		SubResourceClient: c.Client.SubResource(subResource),
+		dryRunClient:      c,
+		subResourceName:   subResource,
+	}
+}
+
+// Create implements client.SubResourceClient
+func (c *dryRunSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return c.dryRunClient.record(ActionCreate, c.subResourceName, obj, nil)
+}
+
+// Patch implements client.SubResourceClient
+func (c *dryRunSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return c.dryRunClient.record(ActionPatch, c.subResourceName, obj, patch)
+}
+
+// Update implements client.SubResourceClient
+func (c *dryRunSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return c.dryRunClient.record(ActionUpdate, c.subResourceName, obj, nil)
+}
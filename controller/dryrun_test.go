@@ -0,0 +1,206 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("DryRun", func() {
+	var (
+		k8sClient client.Client
+		object    *testObject
+	)
+
+	ginkgo.BeforeEach(func() {
+		object = &testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(newTestScheme()).
+			WithObjects(object).
+			WithStatusSubresource(object).
+			Build()
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), object)).To(Succeed())
+	})
+
+	ginkgo.When("NewDryRunClient is called", func() {
+		ginkgo.It("should record the writes in order without sending them", func() {
+			dryRunClient, plan := NewDryRunClient(k8sClient)
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+			Expect(dryRunClient.Create(context.Background(), configMap)).To(Succeed())
+			Expect(dryRunClient.Update(context.Background(), object)).To(Succeed())
+			Expect(dryRunClient.Delete(context.Background(), object)).To(Succeed())
+			Expect(dryRunClient.DeleteAllOf(context.Background(), &corev1.ConfigMap{})).To(Succeed())
+			Expect(dryRunClient.Apply(context.Background(), corev1apply.ConfigMap("config", "default"))).To(Succeed())
+
+			Expect(plan.Actions).To(HaveLen(5))
+			Expect(plan.Actions[0].Verb).To(Equal(ActionCreate))
+			Expect(plan.Actions[0].Key).To(Equal(client.ObjectKey{Name: "config", Namespace: "default"}))
+			Expect(plan.Actions[0].GroupVersionKind.Kind).To(Equal("ConfigMap"))
+			Expect(plan.Actions[0].Object).To(Equal(configMap))
+			Expect(plan.Actions[1].Verb).To(Equal(ActionUpdate))
+			Expect(plan.Actions[1].GroupVersionKind.Kind).To(Equal("TestObject"))
+			Expect(plan.Actions[2].Verb).To(Equal(ActionDelete))
+			Expect(plan.Actions[3].Verb).To(Equal(ActionDeleteAllOf))
+			Expect(plan.Actions[4].Verb).To(Equal(ActionApply))
+			Expect(plan.Actions[4].ApplyConfiguration).NotTo(BeNil())
+
+			err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), &testObject{})).To(Succeed())
+		})
+
+		ginkgo.It("should record the patches along with their content", func() {
+			dryRunClient, plan := NewDryRunClient(k8sClient)
+
+			original := object.DeepCopyObject().(client.Object)
+			object.Labels = map[string]string{"label": "value"}
+			Expect(dryRunClient.Patch(context.Background(), object, client.MergeFrom(original))).To(Succeed())
+
+			Expect(plan.Actions).To(HaveLen(1))
+			Expect(plan.Actions[0].Verb).To(Equal(ActionPatch))
+			Expect(plan.Actions[0].PatchType).To(Equal(types.MergePatchType))
+			Expect(string(plan.Actions[0].Patch)).To(Equal(`{"metadata":{"labels":{"label":"value"}}}`))
+
+			fetched := &testObject{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(fetched.Labels).To(BeEmpty())
+		})
+
+		ginkgo.It("should record the subresource writes", func() {
+			dryRunClient, plan := NewDryRunClient(k8sClient)
+
+			Expect(dryRunClient.Status().Update(context.Background(), object)).To(Succeed())
+			Expect(dryRunClient.SubResource("scale").Patch(context.Background(), object,
+				client.RawPatch(types.MergePatchType, []byte(`{}`)))).To(Succeed())
+
+			Expect(plan.Actions).To(HaveLen(2))
+			Expect(plan.Actions[0].Verb).To(Equal(ActionUpdate))
+			Expect(plan.Actions[0].SubResource).To(Equal("status"))
+			Expect(plan.Actions[1].Verb).To(Equal(ActionPatch))
+			Expect(plan.Actions[1].SubResource).To(Equal("scale"))
+		})
+
+		ginkgo.It("should send reads to the wrapped client", func() {
+			dryRunClient, _ := NewDryRunClient(k8sClient)
+
+			fetched := &testObject{}
+			Expect(dryRunClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(fetched.Name).To(Equal("object"))
+		})
+	})
+
+	ginkgo.When("Handler.DryRun is called", func() {
+		ginkgo.It("should invoke the operations with a dry-run client and return the plan", func() {
+			handler := NewHandler("dry-run")
+
+			result, plan, err := handler.DryRun(context.Background(), object, k8sClient,
+				func(cli client.Client) []NamedOperation {
+					return []NamedOperation{{Name: "create", Operation: func(ctx context.Context) (OperationResult, error) {
+						return RequeueOnErrorOrContinue(cli.Create(ctx, &corev1.ConfigMap{
+							ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+						}))
+					}}}
+				})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(plan.Actions).To(HaveLen(1))
+			Expect(plan.Actions[0].Verb).To(Equal(ActionCreate))
+
+			err = k8sClient.Get(context.Background(), client.ObjectKey{Name: "config", Namespace: "default"}, &corev1.ConfigMap{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		ginkgo.It("should record the status patch as a merge patch", func() {
+			handler := NewHandler("dry-run", WithStatusPatch(k8sClient, ServerSideApplyStrategy))
+
+			_, plan, err := handler.DryRun(context.Background(), object, k8sClient,
+				func(cli client.Client) []NamedOperation {
+					return []NamedOperation{{Name: "status", Operation: func(ctx context.Context) (OperationResult, error) {
+						conditions.SetCondition(&object.Status.Conditions, "Ready", metav1.ConditionTrue, "Ready")
+						return ContinueProcessing()
+					}}}
+				})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Actions).To(HaveLen(1))
+			Expect(plan.Actions[0].Verb).To(Equal(ActionPatch))
+			Expect(plan.Actions[0].SubResource).To(Equal("status"))
+			Expect(plan.Actions[0].PatchType).To(Equal(types.MergePatchType))
+			Expect(object.Name).To(Equal("object"))
+
+			fetched := &testObject{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(fetched.Status.Conditions).To(BeEmpty())
+		})
+
+		ginkgo.It("should not emit events or spans while invoking the rest of the interceptors", func() {
+			recorder := record.NewFakeRecorder(10)
+			exporter := tracetest.NewInMemoryExporter()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			var intercepted []string
+			handler := NewHandler("dry-run",
+				WithEventRecorder(recorder),
+				WithTracerProvider(provider),
+				WithInterceptors(func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+					intercepted = append(intercepted, info.Name)
+					return operation(ctx)
+				}),
+			)
+
+			_, _, err := handler.DryRun(context.Background(), object, k8sClient, func(client.Client) []NamedOperation {
+				return []NamedOperation{{Name: "fail", Operation: ToContextOperation(func() (OperationResult, error) {
+					return RequeueOnErrorOrContinue(fmt.Errorf("error"))
+				})}}
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(intercepted).To(Equal([]string{"fail"}))
+			Expect(recorder.Events).To(BeEmpty())
+			Expect(exporter.GetSpans()).To(BeEmpty())
+		})
+
+		ginkgo.It("should not modify the consecutive failures of the object", func() {
+			handler := NewHandler("dry-run")
+			operation := NamedOperation{Name: "fail", Operation: ToContextOperation(func() (OperationResult, error) {
+				return RequeueWithBackoff(fmt.Errorf("error"))
+			})}
+
+			first, _ := handler.Handle(context.Background(), object, operation)
+			_, _, _ = handler.DryRun(context.Background(), object, k8sClient, func(client.Client) []NamedOperation {
+				return []NamedOperation{operation}
+			})
+			second, _ := handler.Handle(context.Background(), object, operation)
+			Expect(second.RequeueAfter).To(BeNumerically(">", first.RequeueAfter))
+			Expect(second.RequeueAfter).To(BeNumerically("<", 3*DefaultBackoff.Base))
+		})
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type (
+	// backoffError is an error returned by an operation requesting a requeue with backoff.
+	backoffError struct {
+		err error
+	}
+
+	// retryAfterError is an error instructing the handlers to requeue the object after a given delay.
+	retryAfterError struct {
+		delay time.Duration
+		err   error
+	}
+
+	// transientError is an error instructing the handlers to requeue the object using the controller rate limiter.
+	transientError struct {
+		err error
+	}
+)
+
+// Error returns the message of the wrapped error.
+func (e *backoffError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *backoffError) Unwrap() error {
+	return e.err
+}
+
+// Error returns the message of the wrapped error.
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// Error returns the message of the wrapped error.
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfterError wraps the given error so the handlers requeue the object after the given delay. The error is logged
+// but not returned to the controller, as otherwise the delay would be ignored. If err is nil, nil is returned.
+func RetryAfterError(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	return &retryAfterError{delay: delay, err: err}
+}
+
+// TerminalError wraps the given error so the handlers stop processing the object without requeueing it. This is a
+// shortcut for reconcile.TerminalError. If err is nil, nil is returned.
+func TerminalError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return reconcile.TerminalError(err)
+}
+
+// TransientError wraps the given error so the handlers requeue the object using the controller rate limiter, so it
+// backs off exponentially. Any delay requested by the operation is ignored. If err is nil, nil is returned.
+func TransientError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &transientError{err: err}
+}
+
+// GetRetryAfterDelay returns the delay of the first error created with RetryAfterError found in the given error tree.
+// The second value reports whether such an error was found.
+func GetRetryAfterDelay(err error) (time.Duration, bool) {
+	var retryAfterErr *retryAfterError
+	if errors.As(err, &retryAfterErr) {
+		return retryAfterErr.delay, true
+	}
+
+	return 0, false
+}
+
+// IsTerminalError returns whether the given error tree contains an error created with TerminalError or
+// reconcile.TerminalError.
+func IsTerminalError(err error) bool {
+	return errors.Is(err, reconcile.TerminalError(nil))
+}
+
+// IsTransientError returns whether the given error tree contains an error created with TransientError.
+func IsTransientError(err error) bool {
+	var transientErr *transientError
+	return errors.As(err, &transientErr)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = ginkgo.Describe("Errors", func() {
+
+	ginkgo.When("the error constructors are called with a nil error", func() {
+		ginkgo.It("should return nil", func() {
+			Expect(RetryAfterError(nil, time.Minute)).To(BeNil())
+			Expect(TerminalError(nil)).To(BeNil())
+			Expect(TransientError(nil)).To(BeNil())
+		})
+	})
+
+	ginkgo.When("the errors are wrapped", func() {
+		ginkgo.It("should still be classified", func() {
+			Expect(IsTerminalError(fmt.Errorf("wrapped: %w", TerminalError(fmt.Errorf("error"))))).To(BeTrue())
+			Expect(IsTerminalError(reconcile.TerminalError(fmt.Errorf("error")))).To(BeTrue())
+			Expect(IsTransientError(fmt.Errorf("wrapped: %w", TransientError(fmt.Errorf("error"))))).To(BeTrue())
+
+			delay, ok := GetRetryAfterDelay(fmt.Errorf("wrapped: %w", RetryAfterError(fmt.Errorf("error"), time.Minute)))
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(Equal(time.Minute))
+		})
+
+		ginkgo.It("should preserve the original error", func() {
+			err := fmt.Errorf("error")
+			Expect(RetryAfterError(err, time.Minute)).To(MatchError(err))
+			Expect(TerminalError(err)).To(MatchError(err))
+			Expect(TransientError(err)).To(MatchError(err))
+		})
+
+		ginkgo.It("should not classify plain errors", func() {
+			err := fmt.Errorf("error")
+			Expect(IsTerminalError(err)).To(BeFalse())
+			Expect(IsTransientError(err)).To(BeFalse())
+			_, ok := GetRetryAfterDelay(err)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	ginkgo.When("an operation returns a classified error", func() {
+		ginkgo.It("should stop without a requeue on terminal errors", func() {
+			result, err := ReconcileHandler([]Operation{
+				func() (OperationResult, error) {
+					return RequeueAfter(time.Minute, TerminalError(fmt.Errorf("error")))
+				},
+			})
+			Expect(IsTerminalError(err)).To(BeTrue())
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+
+		ginkgo.It("should return the error ignoring the requested delay on transient errors", func() {
+			result, err := ReconcileHandler([]Operation{
+				func() (OperationResult, error) {
+					return RequeueAfter(time.Minute, TransientError(fmt.Errorf("error")))
+				},
+			})
+			Expect(IsTransientError(err)).To(BeTrue())
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+
+		ginkgo.It("should requeue after the error delay without returning the error on retry-after errors", func() {
+			invoked := false
+			result, err := ReconcileHandler([]Operation{
+				func() (OperationResult, error) {
+					return OperationResult{}, RetryAfterError(fmt.Errorf("error"), time.Minute)
+				},
+				func() (OperationResult, error) {
+					invoked = true
+					return ContinueProcessing()
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(invoked).To(BeFalse())
+		})
+	})
+})
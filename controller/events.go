@@ -0,0 +1,76 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// OperationFailedReason is the reason of the events emitted when an operation returns an error.
+	OperationFailedReason = "OperationFailed"
+	// OperationRequeuedReason is the reason of the events emitted when an operation requests a requeue with an error.
+	OperationRequeuedReason = "OperationRequeued"
+	// ProcessingStoppedReason is the reason of the events emitted when an operation stops the processing.
+	ProcessingStoppedReason = "ProcessingStopped"
+)
+
+// EventsInterceptor returns an Interceptor emitting events on the reconciled object using the given recorder. Warning
+// events are emitted when an operation fails or requests a requeue with an error, and Normal events are emitted when
+// an operation stops the processing of the object. No events are emitted if the reconciled object is nil.
+func EventsInterceptor(recorder record.EventRecorder) Interceptor {
+	return func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+		result, err := operation(ctx)
+		if info.Object == nil {
+			return result, err
+		}
+
+		switch {
+		case err != nil && result.RequeueRequest:
+			recorder.Eventf(info.Object, corev1.EventTypeWarning, OperationRequeuedReason,
+				"Operation %s failed and the object will be requeued: %s", info.Name, eventMessage(err))
+		case err != nil:
+			recorder.Eventf(info.Object, corev1.EventTypeWarning, OperationFailedReason,
+				"Operation %s failed: %s", info.Name, eventMessage(err))
+		case result.CancelRequest:
+			recorder.Eventf(info.Object, corev1.EventTypeNormal, ProcessingStoppedReason,
+				"Operation %s stopped the processing of the object", info.Name)
+		}
+
+		return result, err
+	}
+}
+
+// WithEventRecorder makes the Handler emit events describing the outcome of the operations on the reconciled object.
+// It's a shortcut for adding an EventsInterceptor to the Handler.
+func WithEventRecorder(recorder record.EventRecorder) HandlerOption {
+	return withSideEffectInterceptor(EventsInterceptor(recorder))
+}
+
+// eventMessage returns the message describing the given error in events. Stack traces of panics are left out.
+func eventMessage(err error) string {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return panicErr.message()
+	}
+
+	return err.Error()
+}
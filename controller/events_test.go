@@ -0,0 +1,78 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = ginkgo.Describe("Events", func() {
+	var (
+		object   *testObject
+		recorder *record.FakeRecorder
+	)
+
+	handle := func(operation Operation) {
+		handler := NewHandler("events", WithEventRecorder(recorder), WithRecovery(""))
+		_, _ = handler.Handle(context.Background(), object, NamedOperation{
+			Name:      "operation",
+			Operation: ToContextOperation(operation),
+		})
+	}
+
+	ginkgo.BeforeEach(func() {
+		object = &testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	ginkgo.When("a Handler is configured with an event recorder", func() {
+		ginkgo.It("should emit a warning event when an operation requeues with an error", func() {
+			handle(func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("error")) })
+			Expect(recorder.Events).To(Receive(Equal(
+				"Warning OperationRequeued Operation operation failed and the object will be requeued: error")))
+		})
+
+		ginkgo.It("should emit a warning event when an operation fails", func() {
+			handle(func() (OperationResult, error) { return RequeueOnErrorOrStop(fmt.Errorf("error")) })
+			Expect(recorder.Events).To(Receive(Equal("Warning OperationFailed Operation operation failed: error")))
+		})
+
+		ginkgo.It("should leave the stack trace out of events describing panics", func() {
+			handle(func() (OperationResult, error) { panic("panic") })
+			Expect(recorder.Events).To(Receive(Equal("Warning OperationRequeued Operation operation failed and " +
+				`the object will be requeued: operation "operation" panicked: panic`)))
+		})
+
+		ginkgo.It("should emit a normal event when an operation stops the processing", func() {
+			handle(StopProcessing)
+			Expect(recorder.Events).To(Receive(Equal(
+				"Normal ProcessingStopped Operation operation stopped the processing of the object")))
+		})
+
+		ginkgo.It("should not emit events when an operation continues processing or requeues without an error", func() {
+			handle(ContinueProcessing)
+			handle(Requeue)
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// EnsureFinalizer returns a ContextOperation adding the given finalizer to the object unless it's already present or
+// the object is being deleted. The finalizers are patched using an optimistic lock, so the object is requeued if it was
+// modified since it was fetched.
+func EnsureFinalizer(cli client.Client, object client.Object, finalizer string) ContextOperation {
+	return func(ctx context.Context) (OperationResult, error) {
+		if object.GetDeletionTimestamp() != nil {
+			return ContinueProcessing()
+		}
+
+		modified := object.DeepCopyObject().(client.Object)
+		if !controllerutil.AddFinalizer(modified, finalizer) {
+			return ContinueProcessing()
+		}
+
+		return RequeueOnErrorOrContinue(patchFinalizers(ctx, cli, object, modified))
+	}
+}
+
+// Finalize returns a ContextOperation handling the deletion of an object having the given finalizer. If the object is
+// not being deleted, the processing continues. Otherwise, the cleanup operation is invoked and, only if it returns the
+// same result as ContinueProcessing, the finalizer is removed and the processing stops. Any other result or error
+// returned by the cleanup operation is returned as is, keeping the finalizer. Objects being deleted that don't have the
+// finalizer stop the processing without invoking the cleanup.
+func Finalize(cli client.Client, object client.Object, finalizer string, cleanup ContextOperation) ContextOperation {
+	return func(ctx context.Context) (OperationResult, error) {
+		if object.GetDeletionTimestamp() == nil {
+			return ContinueProcessing()
+		}
+
+		if !controllerutil.ContainsFinalizer(object, finalizer) {
+			return StopProcessing()
+		}
+
+		result, err := cleanup(ctx)
+		if err != nil || result != (OperationResult{}) {
+			return result, err
+		}
+
+		modified := object.DeepCopyObject().(client.Object)
+		controllerutil.RemoveFinalizer(modified, finalizer)
+		if err := patchFinalizers(ctx, cli, object, modified); err != nil {
+			return RequeueWithError(err)
+		}
+
+		return StopProcessing()
+	}
+}
+
+// patchFinalizers sends a merge patch with an optimistic lock containing the changes in the finalizers of the modified
+// copy of the object. The patch is sent with the copy, so only the finalizers and the resource version returned by the
+// server are copied back into the object, keeping the changes made to it in memory, such as a status not patched yet.
+func patchFinalizers(ctx context.Context, cli client.Client, object, modified client.Object) error {
+	if err := cli.Patch(ctx, modified, client.MergeFromWithOptions(object, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	object.SetFinalizers(modified.GetFinalizers())
+	object.SetResourceVersion(modified.GetResourceVersion())
+
+	return nil
+}
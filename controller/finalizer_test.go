@@ -0,0 +1,122 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("Finalizer", func() {
+	const finalizer = "test.konflux-ci.dev/finalizer"
+
+	var (
+		k8sClient client.Client
+		object    *testObject
+	)
+
+	ginkgo.BeforeEach(func() {
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(newTestScheme()).
+			WithObjects(&testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}).
+			Build()
+
+		object = &testObject{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "object", Namespace: "default"}, object)).To(Succeed())
+	})
+
+	ginkgo.When("EnsureFinalizer is called", func() {
+		ginkgo.It("should add the finalizer to the object", func() {
+			result, err := EnsureFinalizer(k8sClient, object, finalizer)(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+
+			fetched := &testObject{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(fetched.Finalizers).To(ConsistOf(finalizer))
+		})
+
+		ginkgo.It("should requeue if the object was modified since it was fetched", func() {
+			modified := object.DeepCopyObject().(*testObject)
+			modified.Labels = map[string]string{"modified": "true"}
+			Expect(k8sClient.Update(context.Background(), modified)).To(Succeed())
+
+			_, err := EnsureFinalizer(k8sClient, object, finalizer)(context.Background())
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+		})
+	})
+
+	ginkgo.When("Finalize is called", func() {
+		var cleanupInvoked bool
+
+		deleteObject := func() {
+			_, err := EnsureFinalizer(k8sClient, object, finalizer)(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Delete(context.Background(), object)).To(Succeed())
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), object)).To(Succeed())
+		}
+
+		cleanup := func(result OperationResult, err error) ContextOperation {
+			return func(ctx context.Context) (OperationResult, error) {
+				cleanupInvoked = true
+				return result, err
+			}
+		}
+
+		ginkgo.BeforeEach(func() {
+			cleanupInvoked = false
+		})
+
+		ginkgo.It("should continue processing if the object is not being deleted", func() {
+			result, err := Finalize(k8sClient, object, finalizer, cleanup(ContinueProcessing()))(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(cleanupInvoked).To(BeFalse())
+		})
+
+		ginkgo.It("should run the cleanup and remove the finalizer if the object is being deleted", func() {
+			deleteObject()
+
+			result, err := Finalize(k8sClient, object, finalizer, cleanup(ContinueProcessing()))(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeTrue())
+			Expect(cleanupInvoked).To(BeTrue())
+
+			err = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), &testObject{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		ginkgo.It("should keep the finalizer if the cleanup doesn't finish", func() {
+			deleteObject()
+
+			result, err := Finalize(k8sClient, object, finalizer, cleanup(RequeueWithError(fmt.Errorf("error"))))(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(result.RequeueRequest).To(BeTrue())
+
+			fetched := &testObject{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(fetched.Finalizers).To(ConsistOf(finalizer))
+		})
+	})
+})
@@ -0,0 +1,162 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type (
+	// GraphOperation is a ContextOperation identified by a name that can only be invoked after the operations it
+	// depends on have been invoked and let the processing continue. If Timeout is set, it overrides the default
+	// timeout set with WithOperationTimeout.
+	GraphOperation struct {
+		Name      string
+		Operation ContextOperation
+		DependsOn []string
+		Timeout   time.Duration
+	}
+
+	// GraphReport describes what happened to every operation of a graph after it has been handled.
+	GraphReport struct {
+		// Failed contains the names of the operations that returned an error
+		Failed []string
+		// Ran contains the names of the operations that were invoked and didn't return an error
+		Ran []string
+		// Skipped contains the names of the operations that were not invoked because one of their dependencies
+		// failed, requested a requeue, stopped the processing or was skipped itself
+		Skipped []string
+	}
+)
+
+// HandleGraph will invoke all the operations to be performed as part of the reconcile of the given object in
+// topological order, respecting their dependencies and their declared order when they are independent. When an
+// operation fails, requests a requeue or stops the processing, only the operations depending on it are skipped, while
+// the independent ones are still invoked. Errors from all the operations are joined and, if any operation requested a
+// requeue, the shortest delay is used. The returned GraphReport describes what happened to every operation. An error
+// is returned without invoking any operation if the graph has duplicated names, unknown dependencies or cycles.
+func (h *Handler) HandleGraph(ctx context.Context, object client.Object, operations ...GraphOperation) (ctrl.Result, *GraphReport, error) {
+	ctx, span := h.startReconcileSpan(ctx, object)
+	defer span.End()
+
+	sortedOperations, err := sortGraph(operations)
+	if err != nil {
+		endReconcileSpan(ctx, ctrl.Result{}, err)
+		return ctrl.Result{}, nil, err
+	}
+
+	original := h.snapshot(object)
+	report := &GraphReport{}
+	blocked := map[string]bool{}
+	var requeueResults []OperationResult
+	var errs []error
+	var ctxErr error
+
+	for _, operation := range sortedOperations {
+		if slices.ContainsFunc(operation.DependsOn, func(dependency string) bool { return blocked[dependency] }) {
+			blocked[operation.Name] = true
+			report.Skipped = append(report.Skipped, operation.Name)
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			blocked[operation.Name] = true
+			report.Skipped = append(report.Skipped, operation.Name)
+			if ctxErr == nil {
+				ctxErr = err
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		result, err := h.invoke(operationContext(ctx, object, operation.Name), object, NamedOperation{
+			Name:      operation.Name,
+			Operation: operation.Operation,
+			Timeout:   operation.Timeout,
+		})
+
+		errs = appendError(errs, result, err)
+		if err != nil {
+			report.Failed = append(report.Failed, operation.Name)
+		} else {
+			report.Ran = append(report.Ran, operation.Name)
+		}
+
+		if err != nil || result.RequeueRequest || result.CancelRequest {
+			blocked[operation.Name] = true
+		}
+
+		requeueResults = appendRequeue(requeueResults, result)
+	}
+
+	result, err := h.finish(ctx, object, original, requeueResults, errs)
+
+	return result, report, err
+}
+
+// sortGraph returns the given operations sorted topologically. Independent operations keep their declared order. An
+// error is returned if the graph has duplicated names, unknown dependencies or cycles.
+func sortGraph(operations []GraphOperation) ([]GraphOperation, error) {
+	declared := map[string]bool{}
+	for _, operation := range operations {
+		if declared[operation.Name] {
+			return nil, fmt.Errorf("operation %q is declared more than once", operation.Name)
+		}
+		declared[operation.Name] = true
+	}
+
+	for _, operation := range operations {
+		for _, dependency := range operation.DependsOn {
+			if !declared[dependency] {
+				return nil, fmt.Errorf("operation %q depends on unknown operation %q", operation.Name, dependency)
+			}
+		}
+	}
+
+	sorted := make([]GraphOperation, 0, len(operations))
+	visited := map[string]bool{}
+	for len(sorted) < len(operations) {
+		progressed := false
+		for _, operation := range operations {
+			if visited[operation.Name] {
+				continue
+			}
+
+			if slices.ContainsFunc(operation.DependsOn, func(dependency string) bool { return !visited[dependency] }) {
+				continue
+			}
+
+			visited[operation.Name] = true
+			sorted = append(sorted, operation)
+			progressed = true
+			break
+		}
+
+		if !progressed {
+			return nil, errors.New("operations graph contains a cycle")
+		}
+	}
+
+	return sorted, nil
+}
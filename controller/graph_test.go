@@ -0,0 +1,136 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = ginkgo.Describe("Graph", func() {
+	var invoked []string
+
+	operation := func(name string, operation Operation, dependsOn ...string) GraphOperation {
+		return GraphOperation{
+			Name: name,
+			Operation: func(ctx context.Context) (OperationResult, error) {
+				invoked = append(invoked, name)
+				return operation()
+			},
+			DependsOn: dependsOn,
+		}
+	}
+
+	ginkgo.BeforeEach(func() {
+		invoked = nil
+	})
+
+	ginkgo.When("HandleGraph is called", func() {
+		ginkgo.It("should invoke the operations in topological order", func() {
+			result, report, err := NewHandler("graph").HandleGraph(context.Background(), nil,
+				operation("deploy", ContinueProcessing, "validate", "fetch"),
+				operation("fetch", ContinueProcessing, "validate"),
+				operation("validate", ContinueProcessing),
+				operation("report", ContinueProcessing),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(invoked).To(Equal([]string{"validate", "fetch", "deploy", "report"}))
+			Expect(report.Ran).To(Equal(invoked))
+			Expect(report.Failed).To(BeEmpty())
+			Expect(report.Skipped).To(BeEmpty())
+		})
+
+		ginkgo.It("should only skip the dependents of a failing operation", func() {
+			result, report, err := NewHandler("graph").HandleGraph(context.Background(), nil,
+				operation("fetch", func() (OperationResult, error) {
+					return RequeueWithError(fmt.Errorf("fetch failed"))
+				}),
+				operation("deploy", ContinueProcessing, "fetch"),
+				operation("cleanup", ContinueProcessing, "deploy"),
+				operation("report", ContinueProcessing),
+			)
+			Expect(err).To(MatchError("fetch failed"))
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(report.Ran).To(Equal([]string{"report"}))
+			Expect(report.Failed).To(Equal([]string{"fetch"}))
+			Expect(report.Skipped).To(Equal([]string{"deploy", "cleanup"}))
+		})
+
+		ginkgo.It("should skip the dependents of operations stopping the processing or requesting a requeue", func() {
+			result, report, err := NewHandler("graph").HandleGraph(context.Background(), nil,
+				operation("stop", StopProcessing),
+				operation("requeue", func() (OperationResult, error) { return RequeueAfter(time.Minute, nil) }),
+				operation("afterStop", ContinueProcessing, "stop"),
+				operation("afterRequeue", ContinueProcessing, "requeue"),
+				operation("independent", ContinueProcessing),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(report.Ran).To(Equal([]string{"stop", "requeue", "independent"}))
+			Expect(report.Skipped).To(Equal([]string{"afterStop", "afterRequeue"}))
+		})
+
+		ginkgo.It("should join the errors of independent failing operations", func() {
+			_, report, err := NewHandler("graph").HandleGraph(context.Background(), nil,
+				operation("first", func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("first")) }),
+				operation("second", func() (OperationResult, error) { return RequeueWithError(fmt.Errorf("second")) }),
+			)
+			Expect(err).To(MatchError("first\nsecond"))
+			Expect(report.Failed).To(Equal([]string{"first", "second"}))
+		})
+
+		ginkgo.It("should fail without invoking any operation if the graph is invalid", func() {
+			_, _, err := NewHandler("graph").HandleGraph(context.Background(), nil,
+				operation("first", ContinueProcessing, "second"),
+				operation("second", ContinueProcessing, "first"),
+			)
+			Expect(err).To(MatchError(ContainSubstring("cycle")))
+
+			_, _, err = NewHandler("graph").HandleGraph(context.Background(), nil,
+				operation("first", ContinueProcessing, "unknown"),
+			)
+			Expect(err).To(MatchError(ContainSubstring("unknown operation")))
+
+			_, _, err = NewHandler("graph").HandleGraph(context.Background(), nil,
+				operation("first", ContinueProcessing),
+				operation("first", ContinueProcessing),
+			)
+			Expect(err).To(MatchError(ContainSubstring("more than once")))
+			Expect(invoked).To(BeEmpty())
+		})
+
+		ginkgo.It("should report the context error once when the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, report, err := NewHandler("graph").HandleGraph(ctx, nil,
+				operation("first", ContinueProcessing),
+				operation("second", ContinueProcessing),
+				operation("third", ContinueProcessing),
+			)
+			Expect(err).To(Equal(context.Canceled))
+			Expect(report.Skipped).To(Equal([]string{"first", "second", "third"}))
+			Expect(invoked).To(BeEmpty())
+		})
+	})
+})
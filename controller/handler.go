@@ -16,21 +16,344 @@ limitations under the License.
 
 package controller
 
-import ctrl "sigs.k8s.io/controller-runtime"
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type (
+	// Handler invokes the operations to be performed as part of an object reconcile, managing the queue based on the
+	// operations' results. A Handler is meant to be created once per controller and reused across reconciles.
+	Handler struct {
+		backoff             Backoff
+		backoffTracker      *backoffTracker
+		continueOnError     bool
+		dryRunInterceptor   Interceptor
+		dryRunInterceptors  []Interceptor
+		interceptor         Interceptor
+		interceptors        []Interceptor
+		name                string
+		operationTimeout    time.Duration
+		statusPatcher       *statusPatcher
+		stopObserver        func(ProcessingStop)
+		timeoutRequeueDelay time.Duration
+		tracer              trace.Tracer
+	}
+
+	// HandlerOption defines the signature of functions used to configure a Handler.
+	HandlerOption func(*Handler)
+
+	// NamedOperation is a ContextOperation identified by a name. The name is used to scope the operation logger and
+	// is made available to the interceptors through the OperationInfo. If ContinueOnError is set, errors and requeue
+	// requests from the operation don't interrupt the processing of the object, as if the Handler was configured with
+	// WithContinueOnError. If Timeout is set, it overrides the default timeout set with WithOperationTimeout.
+	NamedOperation struct {
+		Name            string
+		Operation       ContextOperation
+		ContinueOnError bool
+		Timeout         time.Duration
+	}
+
+	// ProcessingStop describes why a Handler stopped invoking operations. If neither Operation nor Err are set, all
+	// the operations were invoked.
+	ProcessingStop struct {
+		// Operation is the name of the operation that interrupted the processing, if any
+		Operation string
+		// Err is the error of the context if it was done before all the operations were invoked
+		Err error
+	}
+)
+
+// NewHandler returns a new Handler for the controller with the given name, configured with the options passed as
+// arguments.
+func NewHandler(name string, options ...HandlerOption) *Handler {
+	handler := &Handler{
+		backoff:        DefaultBackoff,
+		backoffTracker: newBackoffTracker(),
+		name:           name,
+	}
+	for _, option := range options {
+		option(handler)
+	}
+	handler.interceptor = ChainInterceptors(handler.interceptors...)
+	handler.dryRunInterceptor = ChainInterceptors(handler.dryRunInterceptors...)
+
+	return handler
+}
+
+// WithMetrics enables the recording of the duration and outcome of every operation invoked by the Handler. It's a
+// shortcut for adding a MetricsInterceptor to the Handler.
+func WithMetrics() HandlerOption {
+	return withSideEffectInterceptor(MetricsInterceptor())
+}
+
+// WithContinueOnError makes the Handler continue invoking operations after one of them returns an error or requests a
+// requeue. All the errors are joined and returned at the end and, if any operation requested a requeue, the shortest
+// delay is used. Operations stopping the processing without an error still interrupt it.
+func WithContinueOnError() HandlerOption {
+	return func(handler *Handler) {
+		handler.continueOnError = true
+	}
+}
+
+// WithStopObserver makes the Handler call the given function every time Handle stops invoking operations, describing
+// why it stopped.
+func WithStopObserver(observer func(ProcessingStop)) HandlerOption {
+	return func(handler *Handler) {
+		handler.stopObserver = observer
+	}
+}
+
+// Handle will invoke all the operations to be performed as part of the reconcile of the given object, managing the
+// queue based on the operations' results. Each operation receives a context derived from the one passed as an
+// argument, carrying a logger scoped to the operation. If the context is done before an operation is invoked, the
+// processing is interrupted and the context error is returned. If any operation deferred a requeue, the object is
+// requeued after the shortest deferred delay once the processing finishes. The consecutive failures of the object
+// used to compute backoff delays are forgotten once the object is processed without errors.
+func (h *Handler) Handle(ctx context.Context, object client.Object, operations ...NamedOperation) (ctrl.Result, error) {
+	ctx, span := h.startReconcileSpan(ctx, object)
+	defer span.End()
+
+	original := h.snapshot(object)
+	var errs []error
+	var requeueResults []OperationResult
+	var stop ProcessingStop
+
+	for _, operation := range operations {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			stop.Err = err
+			break
+		}
+
+		result, err := h.invoke(operationContext(ctx, object, operation.Name), object, operation)
+		errs = appendError(errs, result, err)
+		requeueResults = appendRequeue(requeueResults, result)
+
+		if err != nil || result.RequeueRequest {
+			if h.continueOnError || operation.ContinueOnError {
+				continue
+			}
+			stop.Operation = operation.Name
+			break
+		}
+
+		if result.CancelRequest {
+			stop.Operation = operation.Name
+			break
+		}
+	}
+
+	if h.stopObserver != nil {
+		h.stopObserver(stop)
+	}
+
+	return h.finish(ctx, object, original, requeueResults, errs)
+}
+
+// finish returns the ctrl.Result and error to be returned to the controller once the processing of an object has
+// finished, given the results of the operations requesting a requeue, either immediately or deferred, and the errors
+// found during the processing. If the Handler patches the object status, the patch is sent at this point, comparing
+// the object with the original snapshot. The outcome is recorded in the reconcile span found in the context.
+func (h *Handler) finish(ctx context.Context, object, original client.Object, requeueResults []OperationResult, errs []error) (ctrl.Result, error) {
+	result, err := h.reconcileResult(ctx, object, original, requeueResults, errs)
+	endReconcileSpan(ctx, result, err)
+
+	return result, err
+}
+
+// reconcileResult patches the object status if needed and maps the results and errors found during the processing of
+// an object to the ctrl.Result and error to be returned to the controller.
+func (h *Handler) reconcileResult(ctx context.Context, object, original client.Object, requeueResults []OperationResult, errs []error) (ctrl.Result, error) {
+	if original != nil {
+		if err := h.statusPatcher.patch(ctx, original, object); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	result := mergeResults(requeueResults)
+
+	if len(errs) > 0 || result.RequeueRequest || result.RequeueDelay > 0 {
+		return h.toReconcileResult(ctx, object, result, errs)
+	}
+
+	h.resetBackoff(object)
+
+	return ctrl.Result{}, nil
+}
+
+// invoke runs a single operation through the Handler interceptors, enforcing its timeout.
+func (h *Handler) invoke(ctx context.Context, object client.Object, operation NamedOperation) (OperationResult, error) {
+	return h.interceptor(ctx, OperationInfo{
+		Controller: h.name,
+		Name:       operation.Name,
+		Object:     object,
+	}, h.withTimeout(operation))
+}
+
+// appendError appends the error returned by an operation to the list, if any. Errors returned by operations requesting
+// a requeue with backoff are wrapped, so they can be told apart from the rest once the processing finishes.
+func appendError(errs []error, result OperationResult, err error) []error {
+	if err == nil {
+		return errs
+	}
+	if result.BackoffRequest {
+		err = &backoffError{err: err}
+	}
+
+	return append(errs, err)
+}
+
+// joinErrors returns the given errors joined, or the error itself if there is only one.
+func joinErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
+
+// appendRequeue appends the given result to the list if it requests a requeue, either immediately or deferred.
+func appendRequeue(results []OperationResult, result OperationResult) []OperationResult {
+	if !result.RequeueRequest && result.RequeueDelay == 0 {
+		return results
+	}
+	result.CancelRequest = false
+
+	return append(results, result)
+}
+
+// operationContext returns a context derived from the given one, carrying a logger scoped to the operation with the
+// given name and to the object being reconciled.
+func operationContext(ctx context.Context, object client.Object, name string) context.Context {
+	logger := log.FromContext(ctx)
+	if object != nil {
+		logger = logger.WithValues("object", client.ObjectKeyFromObject(object))
+	}
+
+	return log.IntoContext(ctx, logger.WithValues("operation", name))
+}
 
 // ReconcileHandler will invoke all the operations to be performed as part of an object reconcile, managing the queue
 // based on the operations' results.
 func ReconcileHandler(operations []Operation) (ctrl.Result, error) {
-	for _, operation := range operations {
-		result, err := operation()
+	contextOperations := make([]ContextOperation, len(operations))
+	for index, operation := range operations {
+		contextOperations[index] = ToContextOperation(operation)
+	}
+
+	return ReconcileHandlerWithContext(context.Background(), contextOperations)
+}
+
+// ReconcileHandlerWithContext will invoke all the operations to be performed as part of an object reconcile, managing
+// the queue based on the operations' results. Each operation receives a context derived from the one passed as an
+// argument, so it inherits its deadline, and carrying a logger scoped to the operation. If the context is done before
+// an operation is invoked, the processing is interrupted and the context error is returned.
+func ReconcileHandlerWithContext(ctx context.Context, operations []ContextOperation) (ctrl.Result, error) {
+	namedOperations := make([]NamedOperation, len(operations))
+	for index, operation := range operations {
+		namedOperations[index] = NamedOperation{Name: strconv.Itoa(index), Operation: operation}
+	}
+
+	return NewHandler("").Handle(ctx, nil, namedOperations...)
+}
+
+// snapshot returns a copy of the given object to be compared with it once the processing finishes, or nil if the
+// Handler doesn't patch the object status.
+func (h *Handler) snapshot(object client.Object) client.Object {
+	if h.statusPatcher == nil || object == nil {
+		return nil
+	}
+
+	return object.DeepCopyObject().(client.Object)
+}
+
+// resetBackoff forgets the consecutive failures of the given object.
+func (h *Handler) resetBackoff(object client.Object) {
+	if object != nil {
+		h.backoffTracker.reset(client.ObjectKeyFromObject(object))
+	}
+}
+
+// toReconcileResult maps the merged result and the errors of the operations that requested a requeue or failed to the
+// ctrl.Result and error to be returned to the controller. Errors created with RetryAfterError and errors returned by
+// operations requesting a requeue with backoff are logged instead of returned, so the delay they request is honored,
+// but only if no other error was found. Otherwise, the remaining errors are returned, so no failure goes unreported,
+// and errors created with TerminalError and TransientError make the result be ignored. A requeue with backoff never
+// waits longer than the shortest delay requested by the operations.
+func (h *Handler) toReconcileResult(ctx context.Context, object client.Object, result OperationResult, errs []error) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var retained, retryAfterErrs, backoffErrs []error
+	var retryAfter time.Duration
+	ignoreResult := false
+
+	for _, err := range errs {
+		var backoffErr *backoffError
+		delay, isRetryAfter := GetRetryAfterDelay(err)
 
 		switch {
-		case err != nil || result.RequeueRequest:
-			return ctrl.Result{RequeueAfter: result.RequeueDelay}, err
-		case result.CancelRequest:
-			return ctrl.Result{}, nil
+		case IsTerminalError(err) || IsTransientError(err):
+			ignoreResult = true
+			retained = append(retained, err)
+		case isRetryAfter:
+			if len(retryAfterErrs) == 0 || delay < retryAfter {
+				retryAfter = delay
+			}
+			retryAfterErrs = append(retryAfterErrs, err)
+		case errors.As(err, &backoffErr):
+			backoffErrs = append(backoffErrs, backoffErr.err)
+		default:
+			retained = append(retained, err)
 		}
 	}
 
-	return ctrl.Result{}, nil
+	if len(retained) > 0 {
+		for _, err := range append(retryAfterErrs, backoffErrs...) {
+			logger.Error(err, "Operation failed")
+		}
+
+		if ignoreResult {
+			return ctrl.Result{}, joinErrors(retained)
+		}
+
+		return ctrl.Result{RequeueAfter: result.RequeueDelay}, joinErrors(retained)
+	}
+
+	if len(retryAfterErrs) > 0 {
+		for _, err := range backoffErrs {
+			logger.Error(err, "Operation failed")
+		}
+		logger.Error(joinErrors(retryAfterErrs), "Operation failed, retrying after delay", "delay", retryAfter)
+
+		return ctrl.Result{RequeueAfter: retryAfter}, nil
+	}
+
+	if result.BackoffRequest || len(backoffErrs) > 0 {
+		failures := 1
+		if object != nil {
+			failures = h.backoffTracker.fail(client.ObjectKeyFromObject(object))
+		}
+
+		delay := h.backoff.Delay(failures)
+		if result.RequeueDelay > 0 && result.RequeueDelay < delay {
+			delay = result.RequeueDelay
+		}
+		if len(backoffErrs) > 0 {
+			logger.Error(joinErrors(backoffErrs), "Operation failed, retrying with backoff", "delay", delay, "failures", failures)
+		}
+
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	h.resetBackoff(object)
+
+	return ctrl.Result{RequeueAfter: result.RequeueDelay}, nil
 }
@@ -0,0 +1,374 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = ginkgo.Describe("Handler", func() {
+
+	ginkgo.When("Handle is called", func() {
+		ginkgo.It("should invoke all the operations if all of them continue processing", func() {
+			var invoked []string
+			operation := func(name string) NamedOperation {
+				return NamedOperation{Name: name, Operation: func(ctx context.Context) (OperationResult, error) {
+					invoked = append(invoked, name)
+					return ContinueProcessing()
+				}}
+			}
+
+			result, err := NewHandler("test").Handle(context.Background(), nil, operation("first"), operation("second"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(invoked).To(Equal([]string{"first", "second"}))
+		})
+
+		ginkgo.It("should record the operations metrics when metrics are enabled", func() {
+			handler := NewHandler("metrics-controller", WithMetrics())
+			_, err := handler.Handle(context.Background(), nil,
+				NamedOperation{Name: "continue", Operation: ToContextOperation(ContinueProcessing)},
+				NamedOperation{Name: "requeue", Operation: ToContextOperation(Requeue)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testutil.ToFloat64(OperationTotal.WithLabelValues("metrics-controller", "continue",
+				OperationOutcomeContinue))).To(Equal(float64(1)))
+			Expect(testutil.ToFloat64(OperationTotal.WithLabelValues("metrics-controller", "requeue",
+				OperationOutcomeRequeue))).To(Equal(float64(1)))
+			Expect(testutil.CollectAndCount(OperationDurationSeconds,
+				"operator_toolkit_operation_duration_seconds")).To(BeNumerically(">=", 2))
+		})
+
+		ginkgo.It("should not record metrics when metrics are disabled", func() {
+			_, err := NewHandler("no-metrics-controller").Handle(context.Background(), nil,
+				NamedOperation{Name: "continue", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testutil.ToFloat64(OperationTotal.WithLabelValues("no-metrics-controller", "continue",
+				OperationOutcomeContinue))).To(BeZero())
+		})
+	})
+
+	ginkgo.When("an operation continues processing deferring a requeue", func() {
+		ginkgo.It("should invoke the following operations and requeue after the earliest deferred delay", func() {
+			invoked := false
+			result, err := ReconcileHandler([]Operation{
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(10 * time.Minute) },
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				func() (OperationResult, error) {
+					invoked = true
+					return ContinueProcessing()
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(invoked).To(BeTrue())
+		})
+
+		ginkgo.It("should keep the deferred requeue when a following operation stops processing", func() {
+			result, err := ReconcileHandler([]Operation{
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				StopProcessing,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		})
+
+		ginkgo.It("should requeue earlier if a following operation requests it", func() {
+			result, err := ReconcileHandler([]Operation{
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				func() (OperationResult, error) { return RequeueAfter(time.Second, nil) },
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Second}))
+		})
+
+		ginkgo.It("should keep the deferred requeue when a following operation requests an immediate requeue", func() {
+			result, err := ReconcileHandler([]Operation{
+				func() (OperationResult, error) { return ContinueAndRequeueAfter(time.Minute) },
+				Requeue,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		})
+
+		ginkgo.It("should not wait longer than the deferred requeue when a following operation requests a backoff", func() {
+			result, err := NewHandler("test", WithBackoff(Backoff{Base: time.Hour})).Handle(context.Background(), nil,
+				NamedOperation{Name: "defer", Operation: func(ctx context.Context) (OperationResult, error) {
+					return ContinueAndRequeueAfter(time.Minute)
+				}},
+				NamedOperation{Name: "backoff", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithBackoff(fmt.Errorf("error"))
+				}},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		})
+	})
+
+	ginkgo.When("Handle is called on a Handler configured to continue on error", func() {
+		ginkgo.It("should invoke all the operations and join their errors", func() {
+			invoked := false
+			result, err := NewHandler("test", WithContinueOnError()).Handle(context.Background(), nil,
+				NamedOperation{Name: "first", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithError(fmt.Errorf("first"))
+				}},
+				NamedOperation{Name: "second", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueOnErrorOrStop(fmt.Errorf("second"))
+				}},
+				NamedOperation{Name: "third", Operation: func(ctx context.Context) (OperationResult, error) {
+					invoked = true
+					return ContinueProcessing()
+				}},
+			)
+			Expect(err).To(MatchError("first\nsecond"))
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(invoked).To(BeTrue())
+		})
+
+		ginkgo.It("should requeue with the shortest delay requested", func() {
+			result, err := NewHandler("test", WithContinueOnError()).Handle(context.Background(), nil,
+				NamedOperation{Name: "first", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueAfter(time.Minute, nil)
+				}},
+				NamedOperation{Name: "second", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueAfter(time.Second, nil)
+				}},
+				NamedOperation{Name: "third", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Second}))
+		})
+
+		ginkgo.It("should still stop processing when an operation cancels the request without an error", func() {
+			invoked := false
+			_, err := NewHandler("test", WithContinueOnError()).Handle(context.Background(), nil,
+				NamedOperation{Name: "stop", Operation: ToContextOperation(StopProcessing)},
+				NamedOperation{Name: "second", Operation: func(ctx context.Context) (OperationResult, error) {
+					invoked = true
+					return ContinueProcessing()
+				}},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(invoked).To(BeFalse())
+		})
+
+		ginkgo.It("should return the other errors when an operation fails with a retry-after error", func() {
+			result, err := NewHandler("test", WithContinueOnError()).Handle(context.Background(), nil,
+				NamedOperation{Name: "retry", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithError(RetryAfterError(fmt.Errorf("retry"), time.Minute))
+				}},
+				NamedOperation{Name: "fail", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithError(fmt.Errorf("fail"))
+				}},
+			)
+			Expect(err).To(MatchError("fail"))
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+
+		ginkgo.It("should return the other errors when an operation requests a requeue with backoff", func() {
+			result, err := NewHandler("test", WithContinueOnError()).Handle(context.Background(), nil,
+				NamedOperation{Name: "backoff", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithBackoff(fmt.Errorf("backoff"))
+				}},
+				NamedOperation{Name: "fail", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithError(fmt.Errorf("fail"))
+				}},
+			)
+			Expect(err).To(MatchError("fail"))
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+
+		ginkgo.It("should give terminal errors precedence over retry-after errors", func() {
+			result, err := NewHandler("test", WithContinueOnError()).Handle(context.Background(), nil,
+				NamedOperation{Name: "retry", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithError(RetryAfterError(fmt.Errorf("retry"), time.Minute))
+				}},
+				NamedOperation{Name: "terminal", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueOnErrorOrStop(TerminalError(fmt.Errorf("terminal")))
+				}},
+			)
+			Expect(IsTerminalError(err)).To(BeTrue())
+			Expect(err).To(MatchError("terminal error: terminal"))
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+
+		ginkgo.It("should requeue after the shortest retry-after delay when no other operation fails", func() {
+			result, err := NewHandler("test", WithContinueOnError()).Handle(context.Background(), nil,
+				NamedOperation{Name: "first", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithError(RetryAfterError(fmt.Errorf("first"), time.Minute))
+				}},
+				NamedOperation{Name: "second", Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithError(RetryAfterError(fmt.Errorf("second"), time.Second))
+				}},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Second}))
+		})
+	})
+
+	ginkgo.When("Handle is called with operations flagged to continue on error", func() {
+		ginkgo.It("should only continue after the errors of the flagged operations", func() {
+			var invoked []string
+			failing := func(name string, continueOnError bool) NamedOperation {
+				return NamedOperation{
+					Name: name,
+					Operation: func(ctx context.Context) (OperationResult, error) {
+						invoked = append(invoked, name)
+						return RequeueWithError(fmt.Errorf("%s", name))
+					},
+					ContinueOnError: continueOnError,
+				}
+			}
+
+			_, err := NewHandler("test").Handle(context.Background(), nil,
+				failing("first", true),
+				failing("second", false),
+				failing("third", true),
+			)
+			Expect(err).To(MatchError("first\nsecond"))
+			Expect(invoked).To(Equal([]string{"first", "second"}))
+		})
+	})
+
+	ginkgo.When("Handle is called on a Handler configured with a stop observer", func() {
+		var stops []ProcessingStop
+		var handler *Handler
+
+		ginkgo.BeforeEach(func() {
+			stops = nil
+			handler = NewHandler("test", WithStopObserver(func(stop ProcessingStop) {
+				stops = append(stops, stop)
+			}))
+		})
+
+		ginkgo.It("should report the operation that interrupted the processing", func() {
+			_, _ = handler.Handle(context.Background(), nil,
+				NamedOperation{Name: "continue", Operation: ToContextOperation(ContinueProcessing)},
+				NamedOperation{Name: "stop", Operation: ToContextOperation(StopProcessing)},
+				NamedOperation{Name: "skipped", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(stops).To(Equal([]ProcessingStop{{Operation: "stop"}}))
+		})
+
+		ginkgo.It("should report nothing if all the operations were invoked", func() {
+			_, _ = handler.Handle(context.Background(), nil,
+				NamedOperation{Name: "requeue", Operation: ToContextOperation(Requeue), ContinueOnError: true},
+			)
+			Expect(stops).To(Equal([]ProcessingStop{{}}))
+		})
+
+		ginkgo.It("should report the context error if the context was done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, _ = handler.Handle(ctx, nil, NamedOperation{Name: "continue", Operation: ToContextOperation(ContinueProcessing)})
+			Expect(stops).To(Equal([]ProcessingStop{{Err: context.Canceled}}))
+		})
+	})
+
+	ginkgo.When("operationOutcome is called", func() {
+		ginkgo.It("should return the outcome matching the result and error", func() {
+			Expect(operationOutcome(ContinueProcessing())).To(Equal(OperationOutcomeContinue))
+			Expect(operationOutcome(Requeue())).To(Equal(OperationOutcomeRequeue))
+			Expect(operationOutcome(StopProcessing())).To(Equal(OperationOutcomeCancel))
+			Expect(operationOutcome(RequeueWithError(fmt.Errorf("error")))).To(Equal(OperationOutcomeError))
+		})
+	})
+
+	ginkgo.When("ReconcileHandlerWithContext is called", func() {
+		ginkgo.It("should invoke all the operations if all of them continue processing", func() {
+			invocations := 0
+			operation := func(ctx context.Context) (OperationResult, error) {
+				invocations++
+				return ContinueProcessing()
+			}
+
+			result, err := ReconcileHandlerWithContext(context.Background(), []ContextOperation{operation, operation})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(invocations).To(Equal(2))
+		})
+
+		ginkgo.It("should requeue with the operation delay and error", func() {
+			result, err := ReconcileHandlerWithContext(context.Background(), []ContextOperation{
+				func(ctx context.Context) (OperationResult, error) {
+					return RequeueAfter(time.Minute, fmt.Errorf("error"))
+				},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+		})
+
+		ginkgo.It("should stop processing when an operation cancels the request", func() {
+			invoked := false
+			result, err := ReconcileHandlerWithContext(context.Background(), []ContextOperation{
+				ToContextOperation(StopProcessing),
+				func(ctx context.Context) (OperationResult, error) {
+					invoked = true
+					return ContinueProcessing()
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(invoked).To(BeFalse())
+		})
+
+		ginkgo.It("should stop processing when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			invoked := false
+			_, err := ReconcileHandlerWithContext(ctx, []ContextOperation{
+				func(ctx context.Context) (OperationResult, error) {
+					cancel()
+					return ContinueProcessing()
+				},
+				func(ctx context.Context) (OperationResult, error) {
+					invoked = true
+					return ContinueProcessing()
+				},
+			})
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(invoked).To(BeFalse())
+		})
+
+		ginkgo.It("should pass the context deadline and a logger to the operations", func() {
+			deadline := time.Now().Add(time.Hour)
+			ctx, cancel := context.WithDeadline(context.Background(), deadline)
+			defer cancel()
+
+			_, err := ReconcileHandlerWithContext(ctx, []ContextOperation{
+				func(ctx context.Context) (OperationResult, error) {
+					operationDeadline, ok := ctx.Deadline()
+					Expect(ok).To(BeTrue())
+					Expect(operationDeadline).To(Equal(deadline))
+					Expect(log.FromContext(ctx).GetSink()).NotTo(BeNil())
+					return ContinueProcessing()
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,92 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type (
+	// Interceptor defines the signature of functions wrapping the invocation of every operation run by a Handler.
+	// An interceptor is expected to call the operation passed as an argument, but it's free to alter its context,
+	// result or error, or to skip calling it at all.
+	Interceptor func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error)
+
+	// OperationInfo contains the details of an operation being invoked by a Handler.
+	OperationInfo struct {
+		// Controller is the name of the controller whose Handler is invoking the operation
+		Controller string
+		// Name is the name of the operation
+		Name string
+		// Object is the object being reconciled. It might be nil
+		Object client.Object
+	}
+)
+
+// ChainInterceptors returns an Interceptor composed of all the interceptors passed as arguments. The first interceptor
+// will be the outermost one, so it will be the first to run before the operation and the last to run after it.
+func ChainInterceptors(interceptors ...Interceptor) Interceptor {
+	return func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+		chainedOperation := operation
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chainedOperation
+			chainedOperation = func(ctx context.Context) (OperationResult, error) {
+				return interceptor(ctx, info, next)
+			}
+		}
+
+		return chainedOperation(ctx)
+	}
+}
+
+// WithInterceptors adds the given interceptors to the Handler. Interceptors are chained in the order in which they are
+// added, so the first one will be the outermost one.
+func WithInterceptors(interceptors ...Interceptor) HandlerOption {
+	return func(handler *Handler) {
+		handler.interceptors = append(handler.interceptors, interceptors...)
+		handler.dryRunInterceptors = append(handler.dryRunInterceptors, interceptors...)
+	}
+}
+
+// withSideEffectInterceptor adds the given interceptor to the Handler like WithInterceptors, but it's left out when
+// the operations are run in dry-run mode, as it emits events, metrics or spans about them.
+func withSideEffectInterceptor(interceptor Interceptor) HandlerOption {
+	return func(handler *Handler) {
+		handler.interceptors = append(handler.interceptors, interceptor)
+	}
+}
+
+// LoggingInterceptor returns an Interceptor logging the start and outcome of every operation. Errors are always
+// logged, while the rest of the messages are only logged at verbosity level 1.
+func LoggingInterceptor() Interceptor {
+	return func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+		logger := log.FromContext(ctx)
+		logger.V(1).Info("Invoking operation")
+
+		result, err := operation(ctx)
+		if err != nil {
+			logger.Error(err, "Operation failed")
+		} else {
+			logger.V(1).Info("Operation finished", "outcome", operationOutcome(result, err))
+		}
+
+		return result, err
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("Interceptor", func() {
+
+	recordingInterceptor := func(name string, calls *[]string) Interceptor {
+		return func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+			*calls = append(*calls, name+":before")
+			result, err := operation(ctx)
+			*calls = append(*calls, name+":after")
+			return result, err
+		}
+	}
+
+	ginkgo.When("ChainInterceptors is called", func() {
+		ginkgo.It("should invoke the operation directly if no interceptors are passed", func() {
+			result, err := ChainInterceptors()(context.Background(), OperationInfo{}, ToContextOperation(StopProcessing))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeTrue())
+		})
+
+		ginkgo.It("should run the interceptors in the order in which they are passed", func() {
+			var calls []string
+			interceptor := ChainInterceptors(recordingInterceptor("first", &calls), recordingInterceptor("second", &calls))
+			_, err := interceptor(context.Background(), OperationInfo{}, func(ctx context.Context) (OperationResult, error) {
+				calls = append(calls, "operation")
+				return ContinueProcessing()
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([]string{"first:before", "second:before", "operation", "second:after", "first:after"}))
+		})
+
+		ginkgo.It("should allow interceptors to override the operation result", func() {
+			interceptor := ChainInterceptors(
+				func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+					_, _ = operation(ctx)
+					return RequeueWithError(fmt.Errorf("intercepted"))
+				},
+			)
+			result, err := interceptor(context.Background(), OperationInfo{}, ToContextOperation(ContinueProcessing))
+			Expect(err).To(MatchError("intercepted"))
+			Expect(result.RequeueRequest).To(BeTrue())
+		})
+	})
+
+	ginkgo.When("a Handler is configured with interceptors", func() {
+		ginkgo.It("should pass the operation details to the interceptors", func() {
+			var infos []OperationInfo
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}}
+			handler := NewHandler("interceptors", WithInterceptors(
+				func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+					infos = append(infos, info)
+					return operation(ctx)
+				},
+			))
+
+			_, err := handler.Handle(context.Background(), pod,
+				NamedOperation{Name: "first", Operation: ToContextOperation(ContinueProcessing)},
+				NamedOperation{Name: "second", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(infos).To(Equal([]OperationInfo{
+				{Controller: "interceptors", Name: "first", Object: pod},
+				{Controller: "interceptors", Name: "second", Object: pod},
+			}))
+		})
+
+		ginkgo.It("should chain the interceptors added by every option", func() {
+			var calls []string
+			handler := NewHandler("interceptors",
+				WithInterceptors(recordingInterceptor("first", &calls)),
+				WithInterceptors(recordingInterceptor("second", &calls), LoggingInterceptor()),
+			)
+
+			_, err := handler.Handle(context.Background(), nil,
+				NamedOperation{Name: "operation", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([]string{"first:before", "second:before", "second:after", "first:after"}))
+		})
+	})
+})
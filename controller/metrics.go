@@ -0,0 +1,104 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// OperationOutcomeCancel is the outcome of operations that stopped the processing of the object.
+	OperationOutcomeCancel = "cancel"
+	// OperationOutcomeContinue is the outcome of operations that let the processing of the object continue.
+	OperationOutcomeContinue = "continue"
+	// OperationOutcomeError is the outcome of operations that returned an error.
+	OperationOutcomeError = "error"
+	// OperationOutcomeRequeue is the outcome of operations that requested the object to be requeued.
+	OperationOutcomeRequeue = "requeue"
+)
+
+var (
+	OperationDurationSeconds = prometheus.NewHistogramVec(
+		OperationDurationSecondsOpts,
+		OperationDurationSecondsLabels,
+	)
+	OperationDurationSecondsLabels = []string{
+		"controller",
+		"operation",
+	}
+	OperationDurationSecondsOpts = prometheus.HistogramOpts{
+		Name:    "operator_toolkit_operation_duration_seconds",
+		Help:    "How long in seconds an operation takes to complete",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}
+
+	OperationTotal = prometheus.NewCounterVec(
+		OperationTotalOpts,
+		OperationTotalLabels,
+	)
+	OperationTotalLabels = []string{
+		"controller",
+		"operation",
+		"outcome",
+	}
+	OperationTotalOpts = prometheus.CounterOpts{
+		Name: "operator_toolkit_operation_total",
+		Help: "Total number of operations invoked, labeled by their outcome",
+	}
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		OperationDurationSeconds,
+		OperationTotal,
+	)
+}
+
+// MetricsInterceptor returns an Interceptor recording the duration and outcome of every operation.
+func MetricsInterceptor() Interceptor {
+	return func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+		startTime := time.Now()
+		result, err := operation(ctx)
+		recordOperation(info.Controller, info.Name, time.Since(startTime), result, err)
+
+		return result, err
+	}
+}
+
+// recordOperation registers the duration and outcome of an operation invoked by the controller with the given name.
+func recordOperation(controller, operation string, duration time.Duration, result OperationResult, err error) {
+	OperationDurationSeconds.WithLabelValues(controller, operation).Observe(duration.Seconds())
+	OperationTotal.WithLabelValues(controller, operation, operationOutcome(result, err)).Inc()
+}
+
+// operationOutcome returns the outcome of an operation based on its result and error.
+func operationOutcome(result OperationResult, err error) string {
+	switch {
+	case err != nil:
+		return OperationOutcomeError
+	case result.RequeueRequest:
+		return OperationOutcomeRequeue
+	case result.CancelRequest:
+		return OperationOutcomeCancel
+	default:
+		return OperationOutcomeContinue
+	}
+}
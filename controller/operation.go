@@ -16,18 +16,36 @@ limitations under the License.
 
 package controller
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
-// OperationResult represents the result of a reconcile operation
+// OperationResult represents the result of a reconcile operation. A RequeueDelay set in a result neither requesting a
+// requeue nor cancelling the request makes the processing continue while ensuring the object is requeued after, at
+// most, the given delay.
 type OperationResult struct {
 	RequeueDelay   time.Duration
 	RequeueRequest bool
 	CancelRequest  bool
+	BackoffRequest bool
 }
 
 // Operation defines the syntax of functions invoked by the ReconcileHandler
 type Operation func() (OperationResult, error)
 
+// ContextOperation defines the syntax of functions invoked by the ReconcileHandlerWithContext. The context passed to
+// the operation carries the reconcile deadline and a logger scoped to the operation.
+type ContextOperation func(ctx context.Context) (OperationResult, error)
+
+// ToContextOperation adapts an Operation so it can be used where a ContextOperation is expected. The context is
+// ignored by the wrapped operation.
+func ToContextOperation(operation Operation) ContextOperation {
+	return func(_ context.Context) (OperationResult, error) {
+		return operation()
+	}
+}
+
 // ContinueProcessing returns an (OperationResult, error) tuple instructing the reconcile loop to continue
 // reconciling of the object.
 func ContinueProcessing() (OperationResult, error) {
@@ -38,6 +56,17 @@ func ContinueProcessing() (OperationResult, error) {
 	}, nil
 }
 
+// ContinueAndRequeueAfter returns an (OperationResult, error) tuple instructing the reconcile loop to continue
+// reconciling the object and to requeue it after the given delay once the processing finishes. If several operations
+// request it, the shortest delay is used.
+func ContinueAndRequeueAfter(delay time.Duration) (OperationResult, error) {
+	return OperationResult{
+		RequeueDelay:   delay,
+		RequeueRequest: false,
+		CancelRequest:  false,
+	}, nil
+}
+
 // Requeue returns an (OperationResult, error) tuple instructing the reconcile loop to requeue the object.
 func Requeue() (OperationResult, error) {
 	return RequeueWithError(nil)
@@ -53,6 +82,18 @@ func RequeueAfter(delay time.Duration, err error) (OperationResult, error) {
 	}, err
 }
 
+// RequeueWithBackoff returns an (OperationResult, error) tuple instructing the reconcile loop to requeue the object
+// after a delay growing exponentially with the number of consecutive times the object failed. The error is logged but
+// not returned to the controller, as otherwise the delay would be ignored.
+func RequeueWithBackoff(err error) (OperationResult, error) {
+	return OperationResult{
+		RequeueDelay:   0,
+		RequeueRequest: true,
+		CancelRequest:  false,
+		BackoffRequest: true,
+	}, err
+}
+
 // RequeueOnErrorOrContinue returns an (OperationResult, error) tuple instructing the reconcile loop to requeue
 // the object in case of an error or to continue reconciling the object.
 func RequeueOnErrorOrContinue(err error) (OperationResult, error) {
@@ -0,0 +1,54 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"github.com/konflux-ci/operator-toolkit/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StopIfPaused returns a ContextOperation stopping the processing of the object if its reconciliation was paused
+// through the utils.PausedAnnotation. If the object implements conditions.Object, the Paused condition is set to
+// true while the object is paused, and to false once it's resumed. Persisting the status is left to the caller.
+func StopIfPaused(object client.Object) ContextOperation {
+	return func(ctx context.Context) (OperationResult, error) {
+		conditionsObject, hasConditions := object.(conditions.Object)
+
+		if utils.IsObjectPaused(object) {
+			if hasConditions {
+				conditions.SetConditionWithMessage(conditionsObject.GetConditions(), conditions.PausedConditionType,
+					metav1.ConditionTrue, conditions.PausedReason,
+					fmt.Sprintf("Reconciliation paused through the %s annotation", utils.PausedAnnotation))
+			}
+
+			return StopProcessing()
+		}
+
+		if hasConditions && meta.IsStatusConditionTrue(*conditionsObject.GetConditions(), conditions.PausedConditionType.String()) {
+			conditions.SetCondition(conditionsObject.GetConditions(), conditions.PausedConditionType,
+				metav1.ConditionFalse, conditions.ResumedReason)
+		}
+
+		return ContinueProcessing()
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"github.com/konflux-ci/operator-toolkit/utils"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("Pause", func() {
+
+	ginkgo.When("StopIfPaused is called", func() {
+		ginkgo.It("should continue processing objects not paused", func() {
+			object := &testObject{}
+			result, err := StopIfPaused(object)(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(object.Status.Conditions).To(BeEmpty())
+		})
+
+		ginkgo.It("should stop processing paused objects and set the Paused condition", func() {
+			object := &testObject{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{utils.PausedAnnotation: "true"},
+			}}
+			result, err := StopIfPaused(object)(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.CancelRequest).To(BeTrue())
+
+			condition := meta.FindStatusCondition(object.Status.Conditions, conditions.PausedConditionType.String())
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(conditions.PausedReason.String()))
+		})
+
+		ginkgo.It("should set the Paused condition to false once the object is resumed", func() {
+			object := &testObject{}
+			conditions.SetCondition(object.GetConditions(), conditions.PausedConditionType, metav1.ConditionTrue,
+				conditions.PausedReason)
+
+			result, err := StopIfPaused(object)(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+
+			condition := meta.FindStatusCondition(object.Status.Conditions, conditions.PausedConditionType.String())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(conditions.ResumedReason.String()))
+		})
+	})
+})
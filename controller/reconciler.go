@@ -0,0 +1,102 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type (
+	// OperationsFactory defines the signature of functions returning the operations to be performed as part of the
+	// reconcile of an object.
+	OperationsFactory[T client.Object] func(ctx context.Context, object T) []NamedOperation
+
+	// Reconciler is a generic reconcile.Reconciler implementing the lifecycle shared by most controllers: it fetches
+	// the object being reconciled, ignoring it if it's not found, invokes the operations built by its
+	// OperationsFactory using its Handler and patches the object status if it was modified. It's meant to be embedded
+	// in structs implementing Controller, so only the Register function has to be written:
+	//
+	//	type FooController struct {
+	//		*controller.Reconciler[*v1alpha1.Foo]
+	//	}
+	//
+	//	func (c *FooController) Register(mgr ctrl.Manager, log *logr.Logger, _ cluster.Cluster) error {
+	//		return ctrl.NewControllerManagedBy(mgr).For(&v1alpha1.Foo{}).Complete(c)
+	//	}
+	Reconciler[T client.Object] struct {
+		client        client.Client
+		handler       *Handler
+		newObject     func() T
+		operations    OperationsFactory[T]
+		statusPatcher *statusPatcher
+	}
+)
+
+// NewReconciler returns a new Reconciler using the given client to fetch objects created by newObject and the given
+// handler to invoke the operations built by the OperationsFactory. If handler is nil, a Handler with no options
+// will be used. Unless the handler was configured using WithStatusPatch, the status will be patched using the
+// MergePatchStrategy.
+func NewReconciler[T client.Object](cli client.Client, handler *Handler, newObject func() T, operations OperationsFactory[T]) *Reconciler[T] {
+	if handler == nil {
+		handler = NewHandler("")
+	}
+
+	return &Reconciler[T]{
+		client:     cli,
+		handler:    handler,
+		newObject:  newObject,
+		operations: operations,
+		statusPatcher: &statusPatcher{
+			client:   cli,
+			strategy: MergePatchStrategy,
+		},
+	}
+}
+
+// Reconcile fetches the object referenced by the request and invokes the operations built for it. If the object
+// status is modified by the operations, it's patched once all of them have been invoked. Objects not found are
+// ignored, as they were most likely deleted after the request was queued.
+func (r *Reconciler[T]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	object := r.newObject()
+	err := r.client.Get(ctx, req.NamespacedName, object)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.handler.backoffTracker.reset(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if r.handler.statusPatcher != nil {
+		return r.handler.Handle(ctx, object, r.operations(ctx, object)...)
+	}
+
+	original := object.DeepCopyObject().(T)
+	result, err := r.handler.Handle(ctx, object, r.operations(ctx, object)...)
+
+	if patchErr := r.statusPatcher.patch(ctx, original, object); patchErr != nil {
+		return ctrl.Result{}, errors.Join(err, patchErr)
+	}
+
+	return result, err
+}
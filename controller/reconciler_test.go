@@ -0,0 +1,121 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = ginkgo.Describe("Reconciler", func() {
+	var (
+		k8sClient client.Client
+		patches   int
+		request   ctrl.Request
+	)
+
+	newObject := func() *testObject {
+		return &testObject{}
+	}
+
+	ginkgo.BeforeEach(func() {
+		patches = 0
+		request = ctrl.Request{NamespacedName: types.NamespacedName{Name: "object", Namespace: "default"}}
+		object := &testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(newTestScheme()).
+			WithObjects(object).
+			WithStatusSubresource(object).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(ctx context.Context, cli client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					patches++
+					return cli.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+				},
+			}).
+			Build()
+	})
+
+	ginkgo.When("Reconcile is called", func() {
+		ginkgo.It("should invoke the operations built for the fetched object", func() {
+			var reconciled *testObject
+			reconciler := NewReconciler(k8sClient, nil, newObject,
+				func(ctx context.Context, object *testObject) []NamedOperation {
+					reconciled = object
+					return []NamedOperation{
+						{Name: "requeue", Operation: func(ctx context.Context) (OperationResult, error) {
+							return RequeueAfter(time.Minute, nil)
+						}},
+					}
+				},
+			)
+
+			result, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(reconciled.Name).To(Equal("object"))
+			Expect(patches).To(BeZero())
+		})
+
+		ginkgo.It("should ignore objects not found", func() {
+			invoked := false
+			reconciler := NewReconciler(k8sClient, nil, newObject,
+				func(ctx context.Context, object *testObject) []NamedOperation {
+					invoked = true
+					return nil
+				},
+			)
+
+			request.Name = "missing"
+			result, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(invoked).To(BeFalse())
+		})
+
+		ginkgo.It("should patch the object status if it was modified by the operations", func() {
+			reconciler := NewReconciler(k8sClient, nil, newObject,
+				func(ctx context.Context, object *testObject) []NamedOperation {
+					return []NamedOperation{
+						{Name: "setCondition", Operation: func(ctx context.Context) (OperationResult, error) {
+							conditions.SetCondition(object.GetConditions(), "Ready", metav1.ConditionTrue, "Succeeded")
+							return ContinueProcessing()
+						}},
+					}
+				},
+			)
+
+			_, err := reconciler.Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(Equal(1))
+
+			object := &testObject{}
+			Expect(k8sClient.Get(context.Background(), request.NamespacedName, object)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(object.Status.Conditions, "Ready")).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,141 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PanicReason is the reason set in the condition reporting an operation panic.
+	PanicReason conditions.ConditionReason = "Panic"
+	// RecoveredReason is the reason set in the condition reporting an operation panic once the operation that panicked
+	// returns without panicking.
+	RecoveredReason conditions.ConditionReason = "Recovered"
+)
+
+// PanicError is the error returned in place of a panic raised by an operation.
+type PanicError struct {
+	// Operation is the name of the operation that panicked
+	Operation string
+	// Stack is the stack trace of the goroutine at the time of the panic
+	Stack []byte
+	// Value is the value passed to panic
+	Value any
+}
+
+// goroutinePanic is a panic raised by an operation invoked in another goroutine, carrying the stack trace of that
+// goroutine so it's not lost when the panic is raised again in the calling one.
+type goroutinePanic struct {
+	stack []byte
+	value any
+}
+
+// String returns the value passed to panic along with the stack trace of the goroutine that panicked, so the
+// information is not lost if the panic is not recovered.
+func (p *goroutinePanic) String() string {
+	return fmt.Sprintf("%v\n\ngoroutine stack:\n%s", p.value, p.stack)
+}
+
+// newGoroutinePanic returns a goroutinePanic for the given value recovered from a panic, capturing the stack trace of
+// the current goroutine. It must be called from the deferred function recovering the panic. Values that are already
+// a goroutinePanic are returned as is, keeping the stack trace of the goroutine that originally panicked.
+func newGoroutinePanic(value any) *goroutinePanic {
+	if recovered, ok := value.(*goroutinePanic); ok {
+		return recovered
+	}
+
+	return &goroutinePanic{stack: debug.Stack(), value: value}
+}
+
+// Error returns the message of the error, including the stack trace.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s\n%s", e.message(), e.Stack)
+}
+
+// message returns the message of the error without the stack trace.
+func (e *PanicError) message() string {
+	return fmt.Sprintf("%s: %v", panickedMessage(e.Operation), e.Value)
+}
+
+// panickedMessage returns the beginning of the message describing a panic raised by the operation with the given name.
+func panickedMessage(operation string) string {
+	return fmt.Sprintf("operation %q panicked", operation)
+}
+
+// RecoveryInterceptor returns an Interceptor turning any panic raised by an operation into a PanicError, requeueing the
+// object. If conditionType is not empty and the reconciled object implements conditions.Object, a condition of that
+// type will be set to true in the object's status describing the panic. Once the operation that panicked returns
+// without panicking, on the same or a later reconcile, the condition is set to false. Persisting the status is left to
+// the caller.
+func RecoveryInterceptor(conditionType conditions.ConditionType) Interceptor {
+	return func(ctx context.Context, info OperationInfo, operation ContextOperation) (result OperationResult, err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				panicErr := &PanicError{
+					Operation: info.Name,
+					Stack:     debug.Stack(),
+					Value:     value,
+				}
+				if recovered, ok := value.(*goroutinePanic); ok {
+					panicErr.Stack = recovered.stack
+					panicErr.Value = recovered.value
+				}
+
+				if object, ok := info.Object.(conditions.Object); ok && conditionType != "" {
+					conditions.SetConditionWithMessage(object.GetConditions(), conditionType, metav1.ConditionTrue,
+						PanicReason, panicErr.message())
+				}
+
+				result, err = RequeueWithError(panicErr)
+			}
+		}()
+
+		result, err = operation(ctx)
+		if object, ok := info.Object.(conditions.Object); ok && conditionType != "" {
+			clearPanicCondition(object, conditionType, info.Name)
+		}
+
+		return result, err
+	}
+}
+
+// clearPanicCondition sets the condition of the given type to false if it's reporting a panic raised by the operation
+// with the given name.
+func clearPanicCondition(object conditions.Object, conditionType conditions.ConditionType, operation string) {
+	condition := meta.FindStatusCondition(*object.GetConditions(), conditionType.String())
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != PanicReason.String() ||
+		!strings.HasPrefix(condition.Message, panickedMessage(operation)+":") {
+		return
+	}
+
+	conditions.SetCondition(object.GetConditions(), conditionType, metav1.ConditionFalse, RecoveredReason)
+}
+
+// WithRecovery enables the recovery of panics raised by the operations invoked by the Handler. It's a shortcut for
+// adding a RecoveryInterceptor to the Handler, so it should be passed after any other option adding interceptors
+// expected to observe the resulting error.
+func WithRecovery(conditionType conditions.ConditionType) HandlerOption {
+	return WithInterceptors(RecoveryInterceptor(conditionType))
+}
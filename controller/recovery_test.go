@@ -0,0 +1,95 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("Recovery", func() {
+
+	panickingOperation := NamedOperation{
+		Name: "panicking",
+		Operation: func(ctx context.Context) (OperationResult, error) {
+			var object *testObject
+			object.Status.Conditions = nil
+			return ContinueProcessing()
+		},
+	}
+
+	ginkgo.When("a Handler is configured with recovery", func() {
+		ginkgo.It("should turn a panic into an error naming the operation and carrying the stack trace", func() {
+			result, err := NewHandler("recovery", WithRecovery("")).Handle(context.Background(), nil, panickingOperation)
+			Expect(err).To(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			var panicErr *PanicError
+			Expect(err).To(BeAssignableToTypeOf(panicErr))
+			panicErr = err.(*PanicError)
+			Expect(panicErr.Operation).To(Equal("panicking"))
+			Expect(string(panicErr.Stack)).To(ContainSubstring("recovery_test.go"))
+			Expect(err.Error()).To(HavePrefix(`operation "panicking" panicked: runtime error`))
+		})
+
+		ginkgo.It("should set the given condition in the reconciled object", func() {
+			object := &testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}
+			_, err := NewHandler("recovery", WithRecovery("ReconcileFailed")).Handle(context.Background(), object,
+				panickingOperation)
+			Expect(err).To(HaveOccurred())
+
+			condition := meta.FindStatusCondition(object.Status.Conditions, "ReconcileFailed")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(PanicReason.String()))
+			Expect(condition.Message).To(HavePrefix(`operation "panicking" panicked`))
+			Expect(condition.Message).NotTo(ContainSubstring("goroutine"))
+		})
+
+		ginkgo.It("should set the given condition to false once the operation that panicked doesn't panic", func() {
+			object := &testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}
+			handler := NewHandler("recovery", WithRecovery("ReconcileFailed"), WithContinueOnError())
+			_, err := handler.Handle(context.Background(), object,
+				panickingOperation,
+				NamedOperation{Name: "continue", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(object.Status.Conditions, "ReconcileFailed")).To(BeTrue())
+
+			_, err = handler.Handle(context.Background(), object,
+				NamedOperation{Name: "panicking", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			condition := meta.FindStatusCondition(object.Status.Conditions, "ReconcileFailed")
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(RecoveredReason.String()))
+		})
+
+		ginkgo.It("should not interfere with operations that don't panic", func() {
+			result, err := NewHandler("recovery", WithRecovery("")).Handle(context.Background(), nil,
+				NamedOperation{Name: "stop", Operation: ToContextOperation(StopProcessing)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,168 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultStateMachineCycleDelay is the delay used by state machines not defining a CycleDelay.
+const DefaultStateMachineCycleDelay = 5 * time.Second
+
+type (
+	// Phase is a stage of the lifecycle of an object. While an object is in a phase, its operations are invoked on
+	// every reconcile and, if all of them let the processing continue, its transitions are evaluated. The phase name is
+	// stored as the reason of the state machine condition, so it must be a valid condition reason. Status and Message
+	// are set in the condition while the object is in the phase, the status defaulting to Unknown.
+	Phase struct {
+		Name        conditions.ConditionReason
+		Operations  []Operation
+		Transitions []Transition
+		Status      metav1.ConditionStatus
+		Message     string
+	}
+
+	// Transition moves an object to the phase named To when its guard is met. A transition without a guard is always
+	// taken.
+	Transition struct {
+		To    conditions.ConditionReason
+		Guard func() bool
+	}
+
+	// StateMachine moves objects through a set of phases, storing the current phase in the condition of the given
+	// type. Objects without the condition start in the Initial phase. CycleDelay is the delay after which the object is
+	// requeued when a transition targets a phase already entered during the reconcile, defaulting to
+	// DefaultStateMachineCycleDelay.
+	StateMachine struct {
+		ConditionType conditions.ConditionType
+		Initial       conditions.ConditionReason
+		Phases        []Phase
+		CycleDelay    time.Duration
+	}
+)
+
+// CurrentPhase returns the name of the phase the given object is in, which is the Initial phase if the object doesn't
+// have the state machine condition yet.
+func (m *StateMachine) CurrentPhase(object conditions.Object) conditions.ConditionReason {
+	condition := meta.FindStatusCondition(*object.GetConditions(), m.ConditionType.String())
+	if condition == nil {
+		return m.Initial
+	}
+
+	return conditions.ConditionReason(condition.Reason)
+}
+
+// Operations returns the operations to be invoked for the given object according to its current phase, to be passed
+// to ReconcileHandler. Objects without the state machine condition enter the Initial phase first. The operations of
+// the current phase come next and, last, an operation evaluating the phase transitions in order and moving the object
+// to the target phase of the first one met. The operations of the new phase are invoked right away, followed by its
+// own transitions, until no transition is met or a transition targets a phase already entered during the reconcile. In
+// that case, the object stays in its phase and is requeued after the CycleDelay, so the transition is taken on the next
+// reconcile. If the object is in an unknown phase, a single operation failing with a terminal error is returned.
+func (m *StateMachine) Operations(object conditions.Object) []Operation {
+	phase, err := m.phase(m.CurrentPhase(object))
+	if err != nil {
+		return []Operation{func() (OperationResult, error) {
+			return RequeueOnErrorOrStop(TerminalError(err))
+		}}
+	}
+
+	var operations []Operation
+	if meta.FindStatusCondition(*object.GetConditions(), m.ConditionType.String()) == nil {
+		operations = append(operations, func() (OperationResult, error) {
+			m.enter(object, phase)
+			return ContinueProcessing()
+		})
+	}
+	operations = append(operations, phase.Operations...)
+
+	return append(operations, m.transition(object, phase))
+}
+
+// transition returns an Operation evaluating the transitions of the given phase.
+func (m *StateMachine) transition(object conditions.Object, phase *Phase) Operation {
+	return func() (OperationResult, error) {
+		return m.advance(object, phase, map[conditions.ConditionReason]bool{phase.Name: true})
+	}
+}
+
+// advance moves the object to the target phase of the first transition of the given phase whose guard is met, then
+// invokes the operations of the new phase and evaluates its transitions in turn. If the new phase was already entered
+// during the reconcile, the object stays in the given phase and is requeued after the CycleDelay instead, so cyclic
+// transitions don't loop forever.
+func (m *StateMachine) advance(object conditions.Object, phase *Phase, visited map[conditions.ConditionReason]bool) (OperationResult, error) {
+	for _, transition := range phase.Transitions {
+		if transition.Guard != nil && !transition.Guard() {
+			continue
+		}
+
+		target, err := m.phase(transition.To)
+		if err != nil {
+			return RequeueOnErrorOrStop(TerminalError(err))
+		}
+		if visited[target.Name] {
+			return RequeueAfter(m.cycleDelay(), nil)
+		}
+		visited[target.Name] = true
+		m.enter(object, target)
+
+		operations := append(slices.Clone(target.Operations), func() (OperationResult, error) {
+			return m.advance(object, target, visited)
+		})
+
+		return Sequence(operations...)()
+	}
+
+	return ContinueProcessing()
+}
+
+// cycleDelay returns the delay after which the object is requeued when a transition targets a phase already entered
+// during the reconcile.
+func (m *StateMachine) cycleDelay() time.Duration {
+	if m.CycleDelay <= 0 {
+		return DefaultStateMachineCycleDelay
+	}
+
+	return m.CycleDelay
+}
+
+// enter sets the state machine condition of the object to the given phase.
+func (m *StateMachine) enter(object conditions.Object, phase *Phase) {
+	status := phase.Status
+	if status == "" {
+		status = metav1.ConditionUnknown
+	}
+
+	conditions.SetConditionWithMessage(object.GetConditions(), m.ConditionType, status, phase.Name, phase.Message)
+}
+
+// phase returns the phase with the given name.
+func (m *StateMachine) phase(name conditions.ConditionReason) (*Phase, error) {
+	for index := range m.Phases {
+		if m.Phases[index].Name == name {
+			return &m.Phases[index], nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown phase %q in state machine of condition %q", name, m.ConditionType)
+}
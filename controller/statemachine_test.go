@@ -0,0 +1,183 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = ginkgo.Describe("StateMachine", func() {
+	const conditionType conditions.ConditionType = "Phase"
+
+	var (
+		failed   bool
+		finished bool
+		invoked  []string
+		machine  *StateMachine
+		object   *testObject
+	)
+
+	record := func(name string) Operation {
+		return func() (OperationResult, error) {
+			invoked = append(invoked, name)
+			return ContinueProcessing()
+		}
+	}
+
+	reconcile := func() ctrl.Result {
+		result, err := ReconcileHandler(machine.Operations(object))
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	condition := func() *metav1.Condition {
+		return meta.FindStatusCondition(object.Status.Conditions, conditionType.String())
+	}
+
+	ginkgo.BeforeEach(func() {
+		failed, finished, invoked = false, false, nil
+		object = &testObject{}
+		machine = &StateMachine{
+			ConditionType: conditionType,
+			Initial:       "Pending",
+			Phases: []Phase{
+				{
+					Name:        "Pending",
+					Operations:  []Operation{record("pending")},
+					Transitions: []Transition{{To: "Running"}},
+				},
+				{
+					Name:       "Running",
+					Operations: []Operation{record("running")},
+					Transitions: []Transition{
+						{To: "Failed", Guard: func() bool { return failed }},
+						{To: "Succeeded", Guard: func() bool { return finished }},
+					},
+				},
+				{Name: "Succeeded", Status: metav1.ConditionTrue, Message: "succeeded"},
+				{Name: "Failed", Status: metav1.ConditionFalse, Message: "failed"},
+			},
+		}
+	})
+
+	ginkgo.When("an object doesn't have the state machine condition", func() {
+		ginkgo.It("should be in the initial phase", func() {
+			Expect(machine.CurrentPhase(object)).To(Equal(conditions.ConditionReason("Pending")))
+		})
+
+		ginkgo.It("should enter the initial phase and invoke its operations", func() {
+			operations := machine.Operations(object)
+			Expect(operations).To(HaveLen(3))
+
+			result, err := operations[0]()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(condition().Reason).To(Equal("Pending"))
+			Expect(condition().Status).To(Equal(metav1.ConditionUnknown))
+
+			_, _ = operations[1]()
+			Expect(invoked).To(Equal([]string{"pending"}))
+		})
+	})
+
+	ginkgo.When("the operations of a phase let the processing continue", func() {
+		ginkgo.It("should take the first transition whose guard is met and invoke the operations of the new phase", func() {
+			Expect(reconcile()).To(Equal(ctrl.Result{}))
+			Expect(machine.CurrentPhase(object)).To(Equal(conditions.ConditionReason("Running")))
+			Expect(invoked).To(Equal([]string{"pending", "running"}))
+
+			operations := machine.Operations(object)
+			Expect(operations).To(HaveLen(2))
+
+			result, err := operations[1]()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(machine.CurrentPhase(object)).To(Equal(conditions.ConditionReason("Running")))
+
+			failed, finished = true, true
+			result, err = operations[1]()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(OperationResult{}))
+			Expect(condition().Reason).To(Equal("Failed"))
+			Expect(condition().Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition().Message).To(Equal("failed"))
+		})
+
+		ginkgo.It("should move the object through the phases across reconciles", func() {
+			Expect(reconcile()).To(Equal(ctrl.Result{}))
+			finished = true
+			Expect(reconcile()).To(Equal(ctrl.Result{}))
+			Expect(invoked).To(Equal([]string{"pending", "running", "running"}))
+			Expect(condition().Reason).To(Equal("Succeeded"))
+			Expect(condition().Status).To(Equal(metav1.ConditionTrue))
+
+			Expect(reconcile()).To(Equal(ctrl.Result{}))
+			Expect(invoked).To(HaveLen(3))
+			Expect(condition().Reason).To(Equal("Succeeded"))
+		})
+
+		ginkgo.It("should requeue the object without entering a phase twice in a reconcile", func() {
+			finished = true
+			machine.Phases[2].Transitions = []Transition{{To: "Pending"}}
+
+			Expect(reconcile()).To(Equal(ctrl.Result{RequeueAfter: DefaultStateMachineCycleDelay}))
+			Expect(invoked).To(Equal([]string{"pending", "running"}))
+			Expect(condition().Reason).To(Equal("Succeeded"))
+
+			machine.CycleDelay = time.Minute
+			Expect(reconcile()).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(invoked).To(Equal([]string{"pending", "running", "pending", "running"}))
+			Expect(condition().Reason).To(Equal("Running"))
+		})
+	})
+
+	ginkgo.When("the operations of a phase interrupt the processing", func() {
+		ginkgo.It("should not evaluate the transitions", func() {
+			machine.Phases[0].Operations = []Operation{StopProcessing}
+
+			Expect(reconcile()).To(Equal(ctrl.Result{}))
+			Expect(machine.CurrentPhase(object)).To(Equal(conditions.ConditionReason("Pending")))
+			Expect(condition()).NotTo(BeNil())
+		})
+	})
+
+	ginkgo.When("a phase is unknown", func() {
+		ginkgo.It("should fail with a terminal error if the object is in an unknown phase", func() {
+			conditions.SetCondition(&object.Status.Conditions, conditionType, metav1.ConditionUnknown, "Unknown")
+
+			_, err := ReconcileHandler(machine.Operations(object))
+			Expect(err).To(MatchError(ContainSubstring(`unknown phase "Unknown"`)))
+			Expect(IsTerminalError(err)).To(BeTrue())
+		})
+
+		ginkgo.It("should fail with a terminal error if a transition targets an unknown phase", func() {
+			machine.Phases[0].Transitions = []Transition{{To: "Unknown"}}
+
+			_, err := ReconcileHandler(machine.Operations(object))
+			Expect(err).To(MatchError(ContainSubstring(`unknown phase "Unknown"`)))
+			Expect(IsTerminalError(err)).To(BeTrue())
+			Expect(condition().Reason).To(Equal("Pending"))
+		})
+	})
+})
@@ -0,0 +1,132 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// defaultFieldOwner is the field owner used in server-side apply patches sent by handlers with no name.
+const defaultFieldOwner = "operator-toolkit"
+
+const (
+	// MergePatchStrategy sends a JSON merge patch of the status.
+	MergePatchStrategy StatusPatchStrategy = "MergePatch"
+	// OptimisticLockMergePatchStrategy sends a JSON merge patch of the status including the object resource version,
+	// so it fails with a conflict if the object was modified since it was fetched or last written by the operations.
+	OptimisticLockMergePatchStrategy StatusPatchStrategy = "OptimisticLockMergePatch"
+	// ServerSideApplyStrategy applies the whole status using server-side apply, forcing the ownership of its fields.
+	ServerSideApplyStrategy StatusPatchStrategy = "ServerSideApply"
+)
+
+type (
+	// StatusPatchStrategy defines how the status of an object is patched.
+	StatusPatchStrategy string
+
+	// statusPatcher patches the status of objects using a given strategy.
+	statusPatcher struct {
+		client     client.Client
+		fieldOwner string
+		strategy   StatusPatchStrategy
+	}
+)
+
+// WithStatusPatch makes the Handler snapshot the status of the object before invoking any operation and, if the status
+// changed once they have been invoked, send a single patch of the status using the given client and strategy. The name
+// of the Handler is used as field owner when the strategy is ServerSideApplyStrategy.
+func WithStatusPatch(cli client.Client, strategy StatusPatchStrategy) HandlerOption {
+	return func(handler *Handler) {
+		fieldOwner := handler.name
+		if fieldOwner == "" {
+			fieldOwner = defaultFieldOwner
+		}
+
+		handler.statusPatcher = &statusPatcher{
+			client:     cli,
+			fieldOwner: fieldOwner,
+			strategy:   strategy,
+		}
+	}
+}
+
+// patch sends a patch of the object status if it differs from the original one.
+func (p *statusPatcher) patch(ctx context.Context, original, object client.Object) error {
+	originalStatus, err := getStatus(original)
+	if err != nil {
+		return err
+	}
+
+	status, err := getStatus(object)
+	if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(originalStatus, status) {
+		return nil
+	}
+
+	switch p.strategy {
+	case OptimisticLockMergePatchStrategy:
+		// The lock is based on the current resource version, as operations may have written the object already
+		base := original.DeepCopyObject().(client.Object)
+		base.SetResourceVersion(object.GetResourceVersion())
+		return p.client.Status().Patch(ctx, object, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	case ServerSideApplyStrategy:
+		return p.apply(ctx, object, status)
+	default:
+		return p.client.Status().Patch(ctx, object, client.MergeFrom(original))
+	}
+}
+
+// apply sends a server-side apply patch containing only the given status and the fields identifying the object. The
+// object is updated with the content returned by the server.
+func (p *statusPatcher) apply(ctx context.Context, object client.Object, status any) error {
+	gvk, err := apiutil.GVKForObject(object, p.client.Scheme())
+	if err != nil {
+		return err
+	}
+
+	applyObject := &unstructured.Unstructured{Object: map[string]any{"status": status}}
+	applyObject.SetGroupVersionKind(gvk)
+	applyObject.SetName(object.GetName())
+	applyObject.SetNamespace(object.GetNamespace())
+
+	// The status writer in this controller-runtime version has no Apply function taking an apply configuration
+	err = p.client.Status().Patch(ctx, applyObject, client.Apply, //nolint:staticcheck
+		client.FieldOwner(p.fieldOwner), client.ForceOwnership)
+	if err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(applyObject.Object, object)
+}
+
+// getStatus returns the status of the given object in its unstructured form.
+func getStatus(object runtime.Object) (any, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return content["status"], nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = ginkgo.Describe("Status", func() {
+	var (
+		k8sClient client.Client
+		object    *testObject
+		patches   []client.Patch
+	)
+
+	setCondition := NamedOperation{
+		Name: "setCondition",
+		Operation: func(ctx context.Context) (OperationResult, error) {
+			conditions.SetCondition(object.GetConditions(), "Ready", metav1.ConditionTrue, "Succeeded")
+			return ContinueProcessing()
+		},
+	}
+
+	ginkgo.BeforeEach(func() {
+		patches = nil
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(newTestScheme()).
+			WithObjects(&testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default"}}).
+			WithStatusSubresource(&testObject{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(ctx context.Context, cli client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					patches = append(patches, patch)
+					return cli.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+				},
+			}).
+			Build()
+
+		object = &testObject{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "object", Namespace: "default"}, object)).To(Succeed())
+	})
+
+	ginkgo.When("a Handler is configured to patch the status", func() {
+		ginkgo.It("should send a single patch once all the operations have been invoked", func() {
+			handler := NewHandler("status", WithStatusPatch(k8sClient, MergePatchStrategy))
+			_, err := handler.Handle(context.Background(), object, setCondition, setCondition)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(HaveLen(1))
+
+			fetched := &testObject{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(fetched.Status.Conditions, "Ready")).To(BeTrue())
+		})
+
+		ginkgo.It("should not send a patch if the status didn't change", func() {
+			handler := NewHandler("status", WithStatusPatch(k8sClient, MergePatchStrategy))
+			_, err := handler.Handle(context.Background(), object,
+				NamedOperation{Name: "continue", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(BeEmpty())
+		})
+
+		ginkgo.It("should fail with a conflict using the optimistic lock strategy if the object was modified", func() {
+			modified := object.DeepCopyObject().(*testObject)
+			modified.Labels = map[string]string{"modified": "true"}
+			Expect(k8sClient.Update(context.Background(), modified)).To(Succeed())
+
+			handler := NewHandler("status", WithStatusPatch(k8sClient, OptimisticLockMergePatchStrategy))
+			_, err := handler.Handle(context.Background(), object, setCondition)
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+		})
+
+		ginkgo.It("should patch the status using the optimistic lock strategy after an operation wrote the object", func() {
+			handler := NewHandler("status", WithStatusPatch(k8sClient, OptimisticLockMergePatchStrategy))
+			_, err := handler.Handle(context.Background(), object,
+				NamedOperation{Name: "finalizer", Operation: EnsureFinalizer(k8sClient, object, "test.konflux-ci.dev/finalizer")},
+				setCondition,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(HaveLen(1))
+
+			fetched := &testObject{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(fetched.Finalizers).To(ConsistOf("test.konflux-ci.dev/finalizer"))
+			Expect(meta.IsStatusConditionTrue(fetched.Status.Conditions, "Ready")).To(BeTrue())
+		})
+
+		ginkgo.It("should keep the status changes made before an operation patched the finalizers", func() {
+			handler := NewHandler("status", WithStatusPatch(k8sClient, MergePatchStrategy))
+			_, err := handler.Handle(context.Background(), object,
+				setCondition,
+				NamedOperation{Name: "finalizer", Operation: EnsureFinalizer(k8sClient, object, "test.konflux-ci.dev/finalizer")},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta.IsStatusConditionTrue(object.Status.Conditions, "Ready")).To(BeTrue())
+
+			fetched := &testObject{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(fetched.Finalizers).To(ConsistOf("test.konflux-ci.dev/finalizer"))
+			Expect(meta.IsStatusConditionTrue(fetched.Status.Conditions, "Ready")).To(BeTrue())
+		})
+
+		ginkgo.It("should keep the status changes made before an operation removed a finalizer", func() {
+			object.Finalizers = []string{"test.konflux-ci.dev/finalizer", "test.konflux-ci.dev/other"}
+			Expect(k8sClient.Update(context.Background(), object)).To(Succeed())
+			Expect(k8sClient.Delete(context.Background(), object)).To(Succeed())
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), object)).To(Succeed())
+
+			handler := NewHandler("status", WithStatusPatch(k8sClient, MergePatchStrategy))
+			_, err := handler.Handle(context.Background(), object,
+				setCondition,
+				NamedOperation{Name: "finalize", Operation: Finalize(k8sClient, object, "test.konflux-ci.dev/finalizer",
+					ToContextOperation(ContinueProcessing))},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			fetched := &testObject{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), fetched)).To(Succeed())
+			Expect(fetched.Finalizers).To(ConsistOf("test.konflux-ci.dev/other"))
+			Expect(meta.IsStatusConditionTrue(fetched.Status.Conditions, "Ready")).To(BeTrue())
+		})
+
+		ginkgo.It("should patch the status even if an operation fails", func() {
+			handler := NewHandler("status", WithStatusPatch(k8sClient, MergePatchStrategy))
+			_, err := handler.Handle(context.Background(), object, setCondition, NamedOperation{
+				Name: "fail",
+				Operation: func(ctx context.Context) (OperationResult, error) {
+					return RequeueWithError(apierrors.NewBadRequest("error"))
+				},
+			})
+			Expect(apierrors.IsBadRequest(err)).To(BeTrue())
+			Expect(patches).To(HaveLen(1))
+		})
+	})
+
+	ginkgo.When("a Handler is configured to patch the status using server-side apply", func() {
+		ginkgo.It("should apply the status owning its fields", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}}
+			podClient := fake.NewClientBuilder().
+				WithObjects(pod).
+				WithStatusSubresource(pod).
+				WithReturnManagedFields().
+				Build()
+			Expect(podClient.Get(context.Background(), client.ObjectKeyFromObject(pod), pod)).To(Succeed())
+
+			handler := NewHandler("status", WithStatusPatch(podClient, ServerSideApplyStrategy))
+			_, err := handler.Handle(context.Background(), pod, NamedOperation{
+				Name: "setMessage",
+				Operation: func(ctx context.Context) (OperationResult, error) {
+					pod.Status.Message = "message"
+					return ContinueProcessing()
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			fetched := &corev1.Pod{}
+			Expect(podClient.Get(context.Background(), client.ObjectKeyFromObject(pod), fetched)).To(Succeed())
+			Expect(fetched.Status.Message).To(Equal("message"))
+			Expect(fetched.ManagedFields).To(ContainElement(HaveField("Manager", "status")))
+		})
+	})
+})
@@ -19,20 +19,92 @@ package controller
 import (
 	"testing"
 
-	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	//+kubebuilder:scaffold:imports
 )
 
 func Test(t *testing.T) {
-	RegisterFailHandler(Fail)
+	RegisterFailHandler(ginkgo.Fail)
 
-	RunSpecs(t, "Controller Suite")
+	ginkgo.RunSpecs(t, "Controller Suite")
 }
 
-var _ = BeforeSuite(func() {
-	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+var _ = ginkgo.BeforeSuite(func() {
+	logf.SetLogger(zap.New(zap.WriteTo(ginkgo.GinkgoWriter), zap.UseDevMode(true)))
 })
+
+// testGroupVersion is the group version used to register the testObject type.
+var testGroupVersion = schema.GroupVersion{Group: "test.konflux-ci.dev", Version: "v1"}
+
+// newTestScheme returns a scheme containing the core types and the testObject type.
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	scheme.AddKnownTypeWithName(testGroupVersion.WithKind("TestObject"), &testObject{})
+	scheme.AddKnownTypeWithName(testGroupVersion.WithKind("TestObjectList"), &testObjectList{})
+	metav1.AddToGroupVersion(scheme, testGroupVersion)
+
+	return scheme
+}
+
+// testObject is a minimal custom resource exposing status conditions, used to test the features relying on them.
+type testObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status testObjectStatus `json:"status,omitempty"`
+}
+
+// testObjectStatus defines the observed state of a testObject.
+type testObjectStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GetConditions returns a pointer to the testObject conditions.
+func (o *testObject) GetConditions() *[]metav1.Condition {
+	return &o.Status.Conditions
+}
+
+// DeepCopyObject implements runtime.Object.
+func (o *testObject) DeepCopyObject() runtime.Object {
+	out := &testObject{TypeMeta: o.TypeMeta}
+	o.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if o.Status.Conditions != nil {
+		out.Status.Conditions = make([]metav1.Condition, len(o.Status.Conditions))
+		for i := range o.Status.Conditions {
+			o.Status.Conditions[i].DeepCopyInto(&out.Status.Conditions[i])
+		}
+	}
+
+	return out
+}
+
+// testObjectList contains a list of testObject.
+type testObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []testObject `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object.
+func (l *testObjectList) DeepCopyObject() runtime.Object {
+	out := &testObjectList{TypeMeta: l.TypeMeta}
+	l.ListMeta.DeepCopyInto(&out.ListMeta)
+	if l.Items != nil {
+		out.Items = make([]testObject, len(l.Items))
+		for i := range l.Items {
+			out.Items[i] = *l.Items[i].DeepCopyObject().(*testObject)
+		}
+	}
+
+	return out
+}
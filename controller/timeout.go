@@ -0,0 +1,80 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrOperationTimeout is the error returned when an operation doesn't finish within its timeout.
+var ErrOperationTimeout = errors.New("operation timed out")
+
+// WithOperationTimeout sets the default timeout of the operations invoked by the Handler, which can be overridden per
+// operation. Operations exceeding their timeout are requeued after the given delay with an error wrapping
+// ErrOperationTimeout. A zero delay requeues the object using the controller rate limiter.
+func WithOperationTimeout(timeout, requeueDelay time.Duration) HandlerOption {
+	return func(handler *Handler) {
+		handler.operationTimeout = timeout
+		handler.timeoutRequeueDelay = requeueDelay
+	}
+}
+
+// Timeout returns a ContextOperation invoking the given one with a context that is cancelled once the timeout
+// expires. Operations are expected to honour the context, so Timeout waits for the operation to return even if the
+// timeout expired, and it never keeps running while the object is processed further. If the timeout expired, the
+// object is requeued after the given delay with an error wrapping ErrOperationTimeout and context.DeadlineExceeded,
+// regardless of the result of the operation. A zero delay requeues the object using the controller rate limiter. If
+// the parent context is done before the timeout expires, its error is returned instead.
+func Timeout(operation ContextOperation, timeout, requeueDelay time.Duration) ContextOperation {
+	return func(ctx context.Context) (OperationResult, error) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := operation(timeoutCtx)
+
+		if parentErr := ctx.Err(); parentErr != nil {
+			return RequeueWithError(parentErr)
+		}
+
+		if timeoutErr := timeoutCtx.Err(); timeoutErr != nil {
+			err := fmt.Errorf("%w after %s: %w", ErrOperationTimeout, timeout, timeoutErr)
+			if requeueDelay > 0 {
+				return RequeueWithError(RetryAfterError(err, requeueDelay))
+			}
+			return RequeueWithError(err)
+		}
+
+		return result, err
+	}
+}
+
+// withTimeout returns the operation of the given NamedOperation wrapped with its timeout or, if it has none, with the
+// default timeout of the Handler.
+func (h *Handler) withTimeout(operation NamedOperation) ContextOperation {
+	timeout := operation.Timeout
+	if timeout == 0 {
+		timeout = h.operationTimeout
+	}
+	if timeout <= 0 {
+		return operation.Operation
+	}
+
+	return Timeout(operation.Operation, timeout, h.timeoutRequeueDelay)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/konflux-ci/operator-toolkit/conditions"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = ginkgo.Describe("Timeout", func() {
+	// hang returns an operation blocking until its context is done, reporting the context error through the channel.
+	hang := func(cancelled chan<- error) ContextOperation {
+		return func(ctx context.Context) (OperationResult, error) {
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return ContinueProcessing()
+		}
+	}
+
+	ginkgo.When("Timeout is called", func() {
+		ginkgo.It("should return the result of operations finishing within the timeout", func() {
+			operation := Timeout(ToContextOperation(StopProcessing), time.Second, time.Minute)
+			result, err := operation(context.Background())
+			Expect(result).To(Equal(OperationResult{CancelRequest: true}))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		ginkgo.It("should cancel the context and requeue after the delay with a timeout error when the timeout expires", func() {
+			cancelled := make(chan error, 1)
+			operation := Timeout(hang(cancelled), 10*time.Millisecond, time.Minute)
+
+			result, err := operation(context.Background())
+			Expect(result.RequeueRequest).To(BeTrue())
+			Expect(err).To(MatchError(ErrOperationTimeout))
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+
+			delay, ok := GetRetryAfterDelay(err)
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(Equal(time.Minute))
+			Eventually(cancelled).Should(Receive(Equal(context.DeadlineExceeded)))
+		})
+
+		ginkgo.It("should not set a retry delay when the requeue delay is zero", func() {
+			operation := Timeout(hang(make(chan error, 1)), 10*time.Millisecond, 0)
+
+			result, err := operation(context.Background())
+			Expect(result).To(Equal(OperationResult{RequeueRequest: true}))
+			Expect(err).To(MatchError(ErrOperationTimeout))
+
+			_, ok := GetRetryAfterDelay(err)
+			Expect(ok).To(BeFalse())
+		})
+
+		ginkgo.It("should return the parent context error when it's done before the timeout expires", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := Timeout(hang(make(chan error, 1)), time.Minute, time.Minute)(ctx)
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(err).NotTo(MatchError(ErrOperationTimeout))
+		})
+
+		ginkgo.It("should raise panics in the calling goroutine", func() {
+			operation := Timeout(func(ctx context.Context) (OperationResult, error) {
+				panic("panic")
+			}, time.Second, time.Minute)
+
+			Expect(func() { _, _ = operation(context.Background()) }).To(PanicWith("panic"))
+		})
+	})
+
+	ginkgo.When("a Handler is configured with a default operation timeout", func() {
+		ginkgo.It("should requeue the object after the delay when an operation exceeds the timeout", func() {
+			cancelled := make(chan error, 1)
+			handler := NewHandler("timeout", WithOperationTimeout(10*time.Millisecond, time.Minute))
+
+			result, err := handler.Handle(context.Background(), nil,
+				NamedOperation{Name: "operation", Operation: hang(cancelled)},
+			)
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(cancelled).Should(Receive())
+		})
+
+		ginkgo.It("should favor the timeout of the operation over the default one", func() {
+			handler := NewHandler("timeout", WithOperationTimeout(time.Minute, time.Minute))
+
+			start := time.Now()
+			result, _ := handler.Handle(context.Background(), nil, NamedOperation{
+				Name:      "operation",
+				Operation: hang(make(chan error, 1)),
+				Timeout:   10 * time.Millisecond,
+			})
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Minute))
+		})
+
+		ginkgo.It("should recover panics raised by operations with a timeout", func() {
+			handler := NewHandler("timeout", WithOperationTimeout(time.Second, time.Minute), WithRecovery(""))
+
+			_, err := handler.Handle(context.Background(), nil, NamedOperation{
+				Name:      "operation",
+				Operation: func(ctx context.Context) (OperationResult, error) { panic("panic") },
+			})
+			Expect(err).To(BeAssignableToTypeOf(&PanicError{}))
+		})
+
+		ginkgo.It("should keep the stack trace of the operation that panicked", func() {
+			handler := NewHandler("timeout", WithOperationTimeout(time.Second, time.Minute), WithRecovery(""))
+
+			_, err := handler.Handle(context.Background(), nil, NamedOperation{
+				Name:      "operation",
+				Operation: ToContextOperation(panickingOperation),
+			})
+			Expect(err).To(BeAssignableToTypeOf(&PanicError{}))
+			Expect(string(err.(*PanicError).Stack)).To(ContainSubstring("controller.panickingOperation("))
+		})
+
+		ginkgo.It("should wait for the operation to return before processing the object further", func() {
+			object := &testObject{}
+			handler := NewHandler("timeout", WithOperationTimeout(10*time.Millisecond, time.Minute))
+
+			_, err := handler.Handle(context.Background(), object, NamedOperation{
+				Name: "operation",
+				Operation: func(ctx context.Context) (OperationResult, error) {
+					<-ctx.Done()
+					time.Sleep(10 * time.Millisecond)
+					conditions.SetCondition(object.GetConditions(), "Finished", metav1.ConditionTrue, "Finished")
+					return ContinueProcessing()
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(object.Status.Conditions).To(HaveLen(1))
+		})
+	})
+
+	ginkgo.When("a graph operation has a timeout", func() {
+		ginkgo.It("should skip its dependents when the timeout expires", func() {
+			handler := NewHandler("timeout")
+
+			result, report, err := handler.HandleGraph(context.Background(), nil,
+				GraphOperation{Name: "first", Operation: hang(make(chan error, 1)), Timeout: 10 * time.Millisecond},
+				GraphOperation{Name: "second", Operation: ToContextOperation(ContinueProcessing), DependsOn: []string{"first"}},
+			)
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(err).To(MatchError(ErrOperationTimeout))
+			Expect(report.Failed).To(Equal([]string{"first"}))
+			Expect(report.Skipped).To(Equal([]string{"second"}))
+		})
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReconcileSpanName is the name of the span created for every reconcile performed by a Handler.
+	ReconcileSpanName = "reconcile"
+
+	// TracerName is the name of the tracer used by the Handler to create spans.
+	TracerName = "github.com/konflux-ci/operator-toolkit/controller"
+)
+
+const (
+	// ControllerAttributeKey is the span attribute holding the name of the controller.
+	ControllerAttributeKey = attribute.Key("operator_toolkit.controller")
+	// ObjectGenerationAttributeKey is the span attribute holding the generation of the object being reconciled.
+	ObjectGenerationAttributeKey = attribute.Key("operator_toolkit.object.generation")
+	// ObjectKeyAttributeKey is the span attribute holding the namespace/name key of the object being reconciled.
+	ObjectKeyAttributeKey = attribute.Key("operator_toolkit.object.key")
+	// OperationAttributeKey is the span attribute holding the name of the operation.
+	OperationAttributeKey = attribute.Key("operator_toolkit.operation")
+	// OutcomeAttributeKey is the span attribute holding the outcome of the operation, as reported by the metrics.
+	OutcomeAttributeKey = attribute.Key("operator_toolkit.outcome")
+	// RequeueAfterAttributeKey is the span attribute holding the delay after which the object will be requeued.
+	RequeueAfterAttributeKey = attribute.Key("operator_toolkit.requeue_after")
+)
+
+// WithTracerProvider enables OpenTelemetry tracing in the Handler. Every reconcile gets a root span and every
+// operation a child span, both carrying the key and generation of the object. Operation spans also carry the outcome
+// of the operation, and errors are recorded in the span that returned them.
+func WithTracerProvider(provider trace.TracerProvider) HandlerOption {
+	return func(handler *Handler) {
+		handler.tracer = provider.Tracer(TracerName)
+		withSideEffectInterceptor(TracingInterceptor(handler.tracer))(handler)
+	}
+}
+
+// TracingInterceptor returns an Interceptor creating a span for every operation with the given tracer. The span is a
+// child of any span found in the context, such as the reconcile span created by a Handler configured with
+// WithTracerProvider.
+func TracingInterceptor(tracer trace.Tracer) Interceptor {
+	return func(ctx context.Context, info OperationInfo, operation ContextOperation) (OperationResult, error) {
+		attributes := append(objectAttributes(info.Object),
+			ControllerAttributeKey.String(info.Controller),
+			OperationAttributeKey.String(info.Name),
+		)
+		ctx, span := tracer.Start(ctx, info.Name, trace.WithAttributes(attributes...))
+		defer span.End()
+
+		result, err := operation(ctx)
+
+		span.SetAttributes(
+			OutcomeAttributeKey.String(operationOutcome(result, err)),
+			RequeueAfterAttributeKey.String(result.RequeueDelay.String()),
+		)
+		recordSpanError(span, err)
+
+		return result, err
+	}
+}
+
+// startReconcileSpan starts the root span of the reconcile of the given object, returning a context carrying it. If
+// the Handler has no tracer, a non-recording span is used.
+func (h *Handler) startReconcileSpan(ctx context.Context, object client.Object) (context.Context, trace.Span) {
+	tracer := h.tracer
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer(TracerName)
+	}
+
+	attributes := append(objectAttributes(object), ControllerAttributeKey.String(h.name))
+
+	return tracer.Start(ctx, ReconcileSpanName, trace.WithAttributes(attributes...))
+}
+
+// endReconcileSpan records the outcome of a reconcile in the span found in the given context.
+func endReconcileSpan(ctx context.Context, result ctrl.Result, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(RequeueAfterAttributeKey.String(result.RequeueAfter.String()))
+	recordSpanError(span, err)
+}
+
+// objectAttributes returns the span attributes identifying the given object.
+func objectAttributes(object client.Object) []attribute.KeyValue {
+	if object == nil {
+		return nil
+	}
+
+	return []attribute.KeyValue{
+		ObjectKeyAttributeKey.String(client.ObjectKeyFromObject(object).String()),
+		ObjectGenerationAttributeKey.Int64(object.GetGeneration()),
+	}
+}
+
+// recordSpanError records the given error in the span, marking it as failed.
+func recordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = ginkgo.Describe("Tracing", func() {
+	var (
+		exporter *tracetest.InMemoryExporter
+		handler  *Handler
+		object   *testObject
+	)
+
+	findSpan := func(name string) tracetest.SpanStub {
+		for _, span := range exporter.GetSpans() {
+			if span.Name == name {
+				return span
+			}
+		}
+		ginkgo.Fail(fmt.Sprintf("span %q not found", name))
+		return tracetest.SpanStub{}
+	}
+
+	ginkgo.BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		handler = NewHandler("tracing", WithTracerProvider(provider))
+		object = &testObject{ObjectMeta: metav1.ObjectMeta{Name: "object", Namespace: "default", Generation: 2}}
+	})
+
+	ginkgo.When("a Handler is configured with a tracer provider", func() {
+		ginkgo.It("should create a root span for the reconcile and a child span per operation", func() {
+			_, err := handler.Handle(context.Background(), object,
+				NamedOperation{Name: "first", Operation: ToContextOperation(ContinueProcessing)},
+				NamedOperation{Name: "second", Operation: ToContextOperation(StopProcessing)},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exporter.GetSpans()).To(HaveLen(3))
+
+			root := findSpan(ReconcileSpanName)
+			Expect(root.Parent.IsValid()).To(BeFalse())
+			Expect(root.Attributes).To(ContainElements(
+				ControllerAttributeKey.String("tracing"),
+				ObjectKeyAttributeKey.String("default/object"),
+				ObjectGenerationAttributeKey.Int64(2),
+			))
+
+			for _, name := range []string{"first", "second"} {
+				span := findSpan(name)
+				Expect(span.Parent.SpanID()).To(Equal(root.SpanContext.SpanID()))
+				Expect(span.Attributes).To(ContainElements(
+					OperationAttributeKey.String(name),
+					ObjectKeyAttributeKey.String("default/object"),
+					ObjectGenerationAttributeKey.Int64(2),
+				))
+			}
+
+			Expect(findSpan("first").Attributes).To(ContainElement(OutcomeAttributeKey.String(OperationOutcomeContinue)))
+			Expect(findSpan("second").Attributes).To(ContainElement(OutcomeAttributeKey.String(OperationOutcomeCancel)))
+		})
+
+		ginkgo.It("should record the requeue delay requested by the operations", func() {
+			_, _ = handler.Handle(context.Background(), object,
+				NamedOperation{Name: "operation", Operation: ToContextOperation(func() (OperationResult, error) {
+					return RequeueAfter(time.Minute, nil)
+				})},
+			)
+
+			Expect(findSpan("operation").Attributes).To(ContainElements(
+				OutcomeAttributeKey.String(OperationOutcomeRequeue),
+				RequeueAfterAttributeKey.String("1m0s"),
+			))
+			Expect(findSpan(ReconcileSpanName).Attributes).To(ContainElement(
+				RequeueAfterAttributeKey.String("1m0s")))
+		})
+
+		ginkgo.It("should record errors in the operation and reconcile spans", func() {
+			_, err := handler.Handle(context.Background(), object,
+				NamedOperation{Name: "operation", Operation: ToContextOperation(func() (OperationResult, error) {
+					return RequeueWithError(fmt.Errorf("error"))
+				})},
+			)
+			Expect(err).To(HaveOccurred())
+
+			for _, name := range []string{ReconcileSpanName, "operation"} {
+				span := findSpan(name)
+				Expect(span.Status.Code).To(Equal(codes.Error))
+				Expect(span.Status.Description).To(Equal("error"))
+				Expect(span.Events).To(ContainElement(HaveField("Name", "exception")))
+			}
+			Expect(findSpan("operation").Attributes).To(ContainElement(
+				OutcomeAttributeKey.String(OperationOutcomeError)))
+		})
+
+		ginkgo.It("should create spans for the operations of a graph", func() {
+			_, _, err := handler.HandleGraph(context.Background(), object,
+				GraphOperation{Name: "first", Operation: ToContextOperation(ContinueProcessing)},
+				GraphOperation{Name: "second", Operation: ToContextOperation(ContinueProcessing), DependsOn: []string{"first"}},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			root := findSpan(ReconcileSpanName)
+			Expect(findSpan("first").Parent.SpanID()).To(Equal(root.SpanContext.SpanID()))
+			Expect(findSpan("second").Parent.SpanID()).To(Equal(root.SpanContext.SpanID()))
+		})
+
+		ginkgo.It("should not set object attributes when there is no object", func() {
+			_, _ = handler.Handle(context.Background(), nil,
+				NamedOperation{Name: "operation", Operation: ToContextOperation(ContinueProcessing)},
+			)
+
+			Expect(findSpan("operation").Attributes).NotTo(ContainElement(
+				HaveField("Key", Equal(ObjectKeyAttributeKey))))
+		})
+	})
+
+	ginkgo.When("a Handler is not configured with a tracer provider", func() {
+		ginkgo.It("should process the operations without creating spans", func() {
+			result, err := NewHandler("tracing").Handle(context.Background(), object,
+				NamedOperation{Name: "operation", Operation: ToContextOperation(ContinueProcessing)},
+			)
+			Expect(result.IsZero()).To(BeTrue())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exporter.GetSpans()).To(BeEmpty())
+		})
+	})
+})
@@ -16,16 +16,32 @@ limitations under the License.
 
 package controller
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
 type (
 	// ValidationFunction defines the signature of validation functions that this validator can invoke.
 	ValidationFunction func() *ValidationResult
 
 	// ValidationResult is a struct containing whether a validation passed and what was the error in case that
-	// it didn't pass.
+	// it didn't pass. If known, Path points to the field that failed the validation.
 	ValidationResult struct {
 		Err   error
+		Path  *field.Path
 		Valid bool
 	}
+
+	// AggregatedValidationResult is a struct containing whether all the validations passed and the results of the
+	// ones that didn't pass.
+	AggregatedValidationResult struct {
+		Failures []*ValidationResult
+		Valid    bool
+	}
 )
 
 // Validate evaluates all the validation functions passed as an argument and returns a ValidationResult indicating
@@ -41,3 +57,56 @@ func Validate(functions ...ValidationFunction) *ValidationResult {
 
 	return &ValidationResult{Valid: true}
 }
+
+// ValidateAll evaluates all the validation functions passed as an argument and returns an AggregatedValidationResult
+// indicating whether the validation passed or not. Unlike Validate, the process is not interrupted when a function
+// fails the validation, so every failure is collected.
+func ValidateAll(functions ...ValidationFunction) *AggregatedValidationResult {
+	aggregated := &AggregatedValidationResult{Valid: true}
+
+	for _, function := range functions {
+		result := function()
+		if !result.Valid || result.Err != nil {
+			aggregated.Failures = append(aggregated.Failures, result)
+			aggregated.Valid = false
+		}
+	}
+
+	return aggregated
+}
+
+// Err returns the errors of all the failures joined, each of them prefixed with its field path when known, or nil if
+// the validation passed.
+func (r *AggregatedValidationResult) Err() error {
+	var errs []error
+	for _, failure := range r.Failures {
+		errs = append(errs, failure.error())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Message returns a single line describing all the failures, each of them prefixed with its field path when known,
+// meant to be used as a condition message. An empty string is returned if the validation passed.
+func (r *AggregatedValidationResult) Message() string {
+	messages := make([]string, len(r.Failures))
+	for index, failure := range r.Failures {
+		messages[index] = failure.error().Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// error returns the error of a failed validation prefixed with its field path when known.
+func (r *ValidationResult) error() error {
+	err := r.Err
+	if err == nil {
+		err = errors.New("validation failed")
+	}
+
+	if r.Path == nil {
+		return err
+	}
+
+	return fmt.Errorf("%s: %w", r.Path, err)
+}
@@ -17,21 +17,23 @@ limitations under the License.
 package controller
 
 import (
+	"errors"
 	"fmt"
-	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-var _ = Describe("Validator", func() {
+var _ = ginkgo.Describe("Validator", func() {
 
-	When("Validate is called", func() {
-		It("should return successfully if no validation functions are passed", func() {
+	ginkgo.When("Validate is called", func() {
+		ginkgo.It("should return successfully if no validation functions are passed", func() {
 			result := Validate()
 			Expect(result.Err).NotTo(HaveOccurred())
 			Expect(result.Valid).To(BeTrue())
 		})
 
-		It("should return successfully if all validation functions succeed", func() {
+		ginkgo.It("should return successfully if all validation functions succeed", func() {
 			result := Validate([]ValidationFunction{
 				func() *ValidationResult {
 					return &ValidationResult{Valid: true}
@@ -44,7 +46,7 @@ var _ = Describe("Validator", func() {
 			Expect(result.Valid).To(BeTrue())
 		})
 
-		It("should fail immediately if a validation function fails", func() {
+		ginkgo.It("should fail immediately if a validation function fails", func() {
 			result := Validate([]ValidationFunction{
 				func() *ValidationResult {
 					return &ValidationResult{Err: fmt.Errorf("validation failed")}
@@ -58,4 +60,52 @@ var _ = Describe("Validator", func() {
 			Expect(result.Valid).To(BeFalse())
 		})
 	})
+
+	ginkgo.When("ValidateAll is called", func() {
+		ginkgo.It("should return successfully if no validation functions are passed", func() {
+			result := ValidateAll()
+			Expect(result.Valid).To(BeTrue())
+			Expect(result.Failures).To(BeEmpty())
+			Expect(result.Err()).NotTo(HaveOccurred())
+			Expect(result.Message()).To(BeEmpty())
+		})
+
+		ginkgo.It("should return successfully if all validation functions succeed", func() {
+			result := ValidateAll(
+				func() *ValidationResult {
+					return &ValidationResult{Valid: true}
+				},
+				func() *ValidationResult {
+					return &ValidationResult{Valid: true}
+				},
+			)
+			Expect(result.Valid).To(BeTrue())
+			Expect(result.Err()).NotTo(HaveOccurred())
+		})
+
+		ginkgo.It("should collect the failures of all the validation functions", func() {
+			invalidName := errors.New("invalid name")
+			result := ValidateAll(
+				func() *ValidationResult {
+					return &ValidationResult{Err: invalidName, Path: field.NewPath("spec", "name")}
+				},
+				func() *ValidationResult {
+					return &ValidationResult{Valid: true}
+				},
+				func() *ValidationResult {
+					return &ValidationResult{Err: fmt.Errorf("missing owner")}
+				},
+				func() *ValidationResult {
+					return &ValidationResult{Path: field.NewPath("spec").Child("replicas")}
+				},
+			)
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.Failures).To(HaveLen(3))
+			Expect(result.Err()).To(MatchError(invalidName))
+			Expect(result.Err().Error()).To(Equal(
+				"spec.name: invalid name\nmissing owner\nspec.replicas: validation failed"))
+			Expect(result.Message()).To(Equal(
+				"spec.name: invalid name; missing owner; spec.replicas: validation failed"))
+		})
+	})
 })
@@ -0,0 +1,51 @@
+/*
+Copyright 2023 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"github.com/konflux-ci/operator-toolkit/utils"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// IgnorePaused implements a default predicate function to ignore the events of objects whose reconciliation was paused
+// through the utils.PausedAnnotation.
+//
+// Update events pausing or resuming the reconciliation are not ignored, so the controller can report the change.
+type IgnorePaused struct {
+	predicate.Funcs
+}
+
+// Create returns false if the object associated with the create event is paused. It will return true otherwise.
+func (IgnorePaused) Create(e event.CreateEvent) bool {
+	return !utils.IsObjectPaused(e.Object)
+}
+
+// Generic returns false if the object associated with the generic event is paused. It will return true otherwise.
+func (IgnorePaused) Generic(e event.GenericEvent) bool {
+	return !utils.IsObjectPaused(e.Object)
+}
+
+// Update returns false if both the old and new objects associated with the update event are paused. It will return
+// true otherwise.
+func (IgnorePaused) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+
+	return !utils.IsObjectPaused(e.ObjectOld) || !utils.IsObjectPaused(e.ObjectNew)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"github.com/konflux-ci/operator-toolkit/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Pause predicate", Ordered, func() {
+
+	When("when IgnorePaused predicate is used", func() {
+		pausedPod := &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{utils.PausedAnnotation: "true"},
+			},
+		}
+
+		It("should process create events for objects not paused", func() {
+			contextEvent := event.CreateEvent{Object: &corev1.Pod{}}
+			Expect(IgnorePaused{}.Create(contextEvent)).To(BeTrue())
+		})
+
+		It("should ignore create events for paused objects", func() {
+			contextEvent := event.CreateEvent{Object: pausedPod}
+			Expect(IgnorePaused{}.Create(contextEvent)).To(BeFalse())
+		})
+
+		It("should process delete events", func() {
+			contextEvent := event.DeleteEvent{Object: pausedPod}
+			Expect(IgnorePaused{}.Delete(contextEvent)).To(BeTrue())
+		})
+
+		It("should ignore generic events for paused objects", func() {
+			contextEvent := event.GenericEvent{Object: pausedPod}
+			Expect(IgnorePaused{}.Generic(contextEvent)).To(BeFalse())
+		})
+
+		It("should ignore update events for objects that remain paused", func() {
+			contextEvent := event.UpdateEvent{ObjectOld: pausedPod, ObjectNew: pausedPod}
+			Expect(IgnorePaused{}.Update(contextEvent)).To(BeFalse())
+		})
+
+		It("should process update events pausing or resuming objects", func() {
+			contextEvent := event.UpdateEvent{ObjectOld: &corev1.Pod{}, ObjectNew: pausedPod}
+			Expect(IgnorePaused{}.Update(contextEvent)).To(BeTrue())
+
+			contextEvent = event.UpdateEvent{ObjectOld: pausedPod, ObjectNew: &corev1.Pod{}}
+			Expect(IgnorePaused{}.Update(contextEvent)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,153 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/konflux-ci/operator-toolkit/controller"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ReconcileStep is the record of an operation invoked during a traced reconcile.
+type ReconcileStep struct {
+	Name   string
+	Result controller.OperationResult
+	Err    error
+}
+
+// ReconcileTrace records the operations invoked during a reconcile, along with their results and errors, the reason
+// why the Handler stopped invoking operations and the final result of the reconcile.
+type ReconcileTrace struct {
+	Steps  []ReconcileStep
+	Stop   *controller.ProcessingStop
+	Result ctrl.Result
+	Err    error
+
+	mutex sync.Mutex
+}
+
+// TraceReconcile invokes the given operations like ReconcileHandler does, recording every step in the returned
+// ReconcileTrace. Operations are named after their index, as in ReconcileHandler.
+func TraceReconcile(operations ...controller.Operation) *ReconcileTrace {
+	namedOperations := make([]controller.NamedOperation, len(operations))
+	for index, operation := range operations {
+		namedOperations[index] = controller.NamedOperation{
+			Name:      strconv.Itoa(index),
+			Operation: controller.ToContextOperation(operation),
+		}
+	}
+
+	return TraceNamedReconcile(context.Background(), namedOperations...)
+}
+
+// TraceNamedReconcile invokes the given operations with a Handler with no options, recording every step in the
+// returned ReconcileTrace.
+func TraceNamedReconcile(ctx context.Context, operations ...controller.NamedOperation) *ReconcileTrace {
+	trace := &ReconcileTrace{}
+	handler := controller.NewHandler("", trace.HandlerOptions()...)
+	trace.Result, trace.Err = handler.Handle(ctx, nil, operations...)
+
+	return trace
+}
+
+// Interceptor returns a controller.Interceptor recording every operation invoked in the trace, so reconciles performed
+// by Handlers with custom options can be traced as well. In that case, the final result isn't recorded, and the
+// StopObserver is needed to record where the processing stopped.
+func (t *ReconcileTrace) Interceptor() controller.Interceptor {
+	return func(ctx context.Context, info controller.OperationInfo, operation controller.ContextOperation) (controller.OperationResult, error) {
+		result, err := operation(ctx)
+
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		t.Steps = append(t.Steps, ReconcileStep{Name: info.Name, Result: result, Err: err})
+
+		return result, err
+	}
+}
+
+// StopObserver returns a function to be passed to controller.WithStopObserver, recording in the trace why the Handler
+// stopped invoking operations.
+func (t *ReconcileTrace) StopObserver() func(controller.ProcessingStop) {
+	return func(stop controller.ProcessingStop) {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		t.Stop = &stop
+	}
+}
+
+// HandlerOptions returns the options making a Handler record its reconciles in the trace, that is its Interceptor and
+// its StopObserver.
+func (t *ReconcileTrace) HandlerOptions() []controller.HandlerOption {
+	return []controller.HandlerOption{
+		controller.WithInterceptors(t.Interceptor()),
+		controller.WithStopObserver(t.StopObserver()),
+	}
+}
+
+// Ran returns the names of the operations invoked, in order.
+func (t *ReconcileTrace) Ran() []string {
+	names := make([]string, len(t.Steps))
+	for index, step := range t.Steps {
+		names[index] = step.Name
+	}
+
+	return names
+}
+
+// StoppedAt returns the name of the operation that interrupted the processing, as reported by the Handler. An empty
+// string is returned if no operation interrupted it, including when the context was done before all the operations
+// were invoked, or if the Handler wasn't configured with the StopObserver of the trace.
+func (t *ReconcileTrace) StoppedAt() string {
+	if t.Stop == nil {
+		return ""
+	}
+
+	return t.Stop.Operation
+}
+
+// Completed returns whether the Handler invoked all the operations, as reported by the Handler. False is returned if
+// the Handler wasn't configured with the StopObserver of the trace.
+func (t *ReconcileTrace) Completed() bool {
+	return t.Stop != nil && t.Stop.Operation == "" && t.Stop.Err == nil
+}
+
+// HaveRun succeeds if exactly the operations with the given names were invoked, in the given order.
+func HaveRun(names ...string) types.GomegaMatcher {
+	return gcustom.MakeMatcher(func(trace *ReconcileTrace) (bool, error) {
+		return slices.Equal(trace.Ran(), names), nil
+	}).WithTemplate("Expected operations {{.To}} run\n{{format .Data 1}}\nbut the operations run were\n{{format .Actual.Ran 1}}", names)
+}
+
+// HaveStoppedAt succeeds if the processing was interrupted by the operation with the given name.
+func HaveStoppedAt(name string) types.GomegaMatcher {
+	return gcustom.MakeMatcher(func(trace *ReconcileTrace) (bool, error) {
+		return trace.StoppedAt() == name, nil
+	}).WithTemplate("Expected processing {{.To}} stop at\n{{format .Data 1}}\nbut it stopped at\n{{format .Actual.StoppedAt 1}}", name)
+}
+
+// HaveCompleted succeeds if the Handler invoked all the operations.
+func HaveCompleted() types.GomegaMatcher {
+	return gcustom.MakeMatcher(func(trace *ReconcileTrace) (bool, error) {
+		return trace.Completed(), nil
+	}).WithTemplate("Expected processing {{.To}} complete\nbut it stopped with\n{{format .Actual.Stop 1}}")
+}
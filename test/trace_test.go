@@ -0,0 +1,136 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/operator-toolkit/controller"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("ReconcileTrace", func() {
+	named := func(name string, operation controller.Operation) controller.NamedOperation {
+		return controller.NamedOperation{Name: name, Operation: controller.ToContextOperation(operation)}
+	}
+
+	When("TraceReconcile is called", func() {
+		It("should record every operation invoked named after its index", func() {
+			trace := TraceReconcile(controller.ContinueProcessing, controller.StopProcessing, controller.ContinueProcessing)
+			Expect(trace).To(HaveRun("0", "1"))
+			Expect(trace).To(HaveStoppedAt("1"))
+			Expect(trace.Steps[1].Result).To(Equal(controller.OperationResult{CancelRequest: true}))
+		})
+
+		It("should record the final result of the reconcile", func() {
+			trace := TraceReconcile(func() (controller.OperationResult, error) {
+				return controller.RequeueAfter(time.Minute, nil)
+			})
+			Expect(trace.Result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			Expect(trace.Err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("TraceNamedReconcile is called", func() {
+		It("should record the result and error of every step and where the processing stopped", func() {
+			trace := TraceNamedReconcile(context.Background(),
+				named("fetch", controller.ContinueProcessing),
+				named("validate", func() (controller.OperationResult, error) {
+					return controller.RequeueWithError(fmt.Errorf("invalid"))
+				}),
+				named("deploy", controller.ContinueProcessing),
+			)
+			Expect(trace).To(HaveRun("fetch", "validate"))
+			Expect(trace).To(HaveStoppedAt("validate"))
+			Expect(trace).NotTo(HaveCompleted())
+			Expect(trace.Steps[1].Err).To(MatchError("invalid"))
+			Expect(trace.Err).To(MatchError("invalid"))
+		})
+
+		It("should report the processing as completed when no operation interrupted it", func() {
+			trace := TraceNamedReconcile(context.Background(),
+				named("fetch", controller.ContinueProcessing),
+				named("deploy", controller.ContinueProcessing),
+			)
+			Expect(trace).To(HaveRun("fetch", "deploy"))
+			Expect(trace).To(HaveCompleted())
+			Expect(trace).To(HaveStoppedAt(""))
+		})
+
+		It("should not report the processing as completed when the context was done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			trace := TraceNamedReconcile(ctx, named("fetch", controller.ContinueProcessing))
+			Expect(trace).To(HaveRun())
+			Expect(trace).NotTo(HaveCompleted())
+			Expect(trace).To(HaveStoppedAt(""))
+			Expect(trace.Stop.Err).To(MatchError(context.Canceled))
+		})
+
+		It("should not report an operation failing with continue-on-error as interrupting the processing", func() {
+			trace := TraceNamedReconcile(context.Background(),
+				named("fetch", controller.ContinueProcessing),
+				controller.NamedOperation{
+					Name:            "notify",
+					Operation:       controller.ToContextOperation(controller.Requeue),
+					ContinueOnError: true,
+				},
+			)
+			Expect(trace).To(HaveRun("fetch", "notify"))
+			Expect(trace).To(HaveCompleted())
+			Expect(trace).To(HaveStoppedAt(""))
+		})
+	})
+
+	When("the trace interceptor is used by a custom Handler", func() {
+		It("should record the operations invoked", func() {
+			trace := &ReconcileTrace{}
+			handler := controller.NewHandler("trace", append(trace.HandlerOptions(), controller.WithContinueOnError())...)
+
+			_, _ = handler.Handle(context.Background(), nil,
+				named("first", controller.Requeue),
+				named("second", controller.ContinueProcessing),
+			)
+			Expect(trace).To(HaveRun("first", "second"))
+			Expect(trace).To(HaveCompleted())
+		})
+	})
+
+	When("the matchers fail", func() {
+		It("should describe the path taken", func() {
+			trace := TraceReconcile(controller.ContinueProcessing, controller.StopProcessing)
+
+			matcher := HaveRun("0")
+			Expect(matcher.Match(trace)).To(BeFalse())
+			Expect(matcher.FailureMessage(trace)).To(ContainSubstring("but the operations run were\n    <[]string | len:2, cap:2>: [\"0\", \"1\"]"))
+
+			matcher = HaveStoppedAt("0")
+			Expect(matcher.Match(trace)).To(BeFalse())
+			Expect(matcher.FailureMessage(trace)).To(ContainSubstring("but it stopped at\n    <string>: 1"))
+
+			matcher = HaveCompleted()
+			Expect(matcher.Match(trace)).To(BeFalse())
+			Expect(matcher.FailureMessage(trace)).To(ContainSubstring("but it stopped with\n"))
+			Expect(matcher.FailureMessage(trace)).To(ContainSubstring(`Operation: "1"`))
+		})
+	})
+})
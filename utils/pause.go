@@ -0,0 +1,31 @@
+/*
+Copyright 2023 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/konflux-ci/operator-toolkit/metadata"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PausedAnnotation is the annotation used to pause the reconciliation of an object when set to "true".
+const PausedAnnotation = "operator-toolkit.konflux-ci.dev/paused"
+
+// IsObjectPaused returns whether the reconciliation of the object passed as an argument was paused through the
+// PausedAnnotation or not.
+func IsObjectPaused(object client.Object) bool {
+	return metadata.HasAnnotationWithValue(object, PausedAnnotation, "true")
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2023 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Pause", func() {
+
+	When("IsObjectPaused is called", func() {
+		It("should return false if the object does not contain the paused annotation", func() {
+			pod := &corev1.Pod{}
+
+			Expect(IsObjectPaused(pod)).To(BeFalse())
+		})
+
+		It("should return false if the paused annotation is not set to true", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{PausedAnnotation: "false"},
+				},
+			}
+
+			Expect(IsObjectPaused(pod)).To(BeFalse())
+		})
+
+		It("should return true if the paused annotation is set to true", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{PausedAnnotation: "true"},
+				},
+			}
+
+			Expect(IsObjectPaused(pod)).To(BeTrue())
+		})
+	})
+})